@@ -0,0 +1,13 @@
+// Package trackerpb is the gRPC API of crypto-tracker: the Tracker service definition in
+// tracker.proto and its generated Go messages, client and server interface. Other services
+// import it to call `crypto-tracker serve --grpc-listen`:
+//
+//	conn, err := grpc.NewClient("localhost:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	client := trackerpb.NewTrackerClient(conn)
+//	stream, err := client.TrackWallet(ctx, &trackerpb.TrackWalletRequest{Address: "0x..."})
+//
+// Regenerate the code after changing tracker.proto with protoc, protoc-gen-go and
+// protoc-gen-go-grpc on the PATH.
+package trackerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tracker.proto
@@ -0,0 +1,229 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: tracker.proto
+
+// Tracker fetches, summarizes and values the on-chain history of wallets. It is served by
+// `crypto-tracker serve --grpc-listen` and backed by the same tracker as the CLI.
+
+package trackerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Tracker_TrackWallet_FullMethodName = "/cryptotracker.v1.Tracker/TrackWallet"
+	Tracker_GetSummary_FullMethodName  = "/cryptotracker.v1.Tracker/GetSummary"
+	Tracker_GetBalances_FullMethodName = "/cryptotracker.v1.Tracker/GetBalances"
+)
+
+// TrackerClient is the client API for Tracker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TrackerClient interface {
+	// TrackWallet fetches the complete history of an address and streams each transaction
+	// as soon as its page has been processed, in fetch order. Cancelling the call stops
+	// fetching at the next transaction.
+	TrackWallet(ctx context.Context, in *TrackWalletRequest, opts ...grpc.CallOption) (Tracker_TrackWalletClient, error)
+	// GetSummary counts the history of an address by type, direction and asset.
+	GetSummary(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*Summary, error)
+	// GetBalances replays the history of an address into per-asset quantities, now or at a
+	// past block or time.
+	GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*Balances, error)
+}
+
+type trackerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackerClient(cc grpc.ClientConnInterface) TrackerClient {
+	return &trackerClient{cc}
+}
+
+func (c *trackerClient) TrackWallet(ctx context.Context, in *TrackWalletRequest, opts ...grpc.CallOption) (Tracker_TrackWalletClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Tracker_ServiceDesc.Streams[0], Tracker_TrackWallet_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trackerTrackWalletClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tracker_TrackWalletClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type trackerTrackWalletClient struct {
+	grpc.ClientStream
+}
+
+func (x *trackerTrackWalletClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trackerClient) GetSummary(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*Summary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Summary)
+	err := c.cc.Invoke(ctx, Tracker_GetSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerClient) GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*Balances, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Balances)
+	err := c.cc.Invoke(ctx, Tracker_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackerServer is the server API for Tracker service.
+// All implementations must embed UnimplementedTrackerServer
+// for forward compatibility
+type TrackerServer interface {
+	// TrackWallet fetches the complete history of an address and streams each transaction
+	// as soon as its page has been processed, in fetch order. Cancelling the call stops
+	// fetching at the next transaction.
+	TrackWallet(*TrackWalletRequest, Tracker_TrackWalletServer) error
+	// GetSummary counts the history of an address by type, direction and asset.
+	GetSummary(context.Context, *SummaryRequest) (*Summary, error)
+	// GetBalances replays the history of an address into per-asset quantities, now or at a
+	// past block or time.
+	GetBalances(context.Context, *BalancesRequest) (*Balances, error)
+	mustEmbedUnimplementedTrackerServer()
+}
+
+// UnimplementedTrackerServer must be embedded to have forward compatible implementations.
+type UnimplementedTrackerServer struct {
+}
+
+func (UnimplementedTrackerServer) TrackWallet(*TrackWalletRequest, Tracker_TrackWalletServer) error {
+	return status.Errorf(codes.Unimplemented, "method TrackWallet not implemented")
+}
+func (UnimplementedTrackerServer) GetSummary(context.Context, *SummaryRequest) (*Summary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSummary not implemented")
+}
+func (UnimplementedTrackerServer) GetBalances(context.Context, *BalancesRequest) (*Balances, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedTrackerServer) mustEmbedUnimplementedTrackerServer() {}
+
+// UnsafeTrackerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackerServer will
+// result in compilation errors.
+type UnsafeTrackerServer interface {
+	mustEmbedUnimplementedTrackerServer()
+}
+
+func RegisterTrackerServer(s grpc.ServiceRegistrar, srv TrackerServer) {
+	s.RegisterService(&Tracker_ServiceDesc, srv)
+}
+
+func _Tracker_TrackWallet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TrackWalletRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerServer).TrackWallet(m, &trackerTrackWalletServer{ServerStream: stream})
+}
+
+type Tracker_TrackWalletServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type trackerTrackWalletServer struct {
+	grpc.ServerStream
+}
+
+func (x *trackerTrackWalletServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Tracker_GetSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServer).GetSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tracker_GetSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServer).GetSummary(ctx, req.(*SummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tracker_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tracker_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServer).GetBalances(ctx, req.(*BalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tracker_ServiceDesc is the grpc.ServiceDesc for Tracker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tracker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cryptotracker.v1.Tracker",
+	HandlerType: (*TrackerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSummary",
+			Handler:    _Tracker_GetSummary_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _Tracker_GetBalances_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TrackWallet",
+			Handler:       _Tracker_TrackWallet_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tracker.proto",
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/report"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Show current wallet holdings",
+	Long: `Replays the complete processed transaction history and reports the current per-asset
+quantities, optionally valued in fiat and with the NFT inventory.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		transactions, owned, err := loadHistory()
+		if err != nil {
+			return err
+		}
+
+		return showHoldings(transactions, owned, report.Cutoff{Time: time.Now().UTC()})
+	},
+}
+
+func init() {
+	addHistoryFlags(balanceCmd)
+	addHoldingsFlags(balanceCmd)
+	rootCmd.AddCommand(balanceCmd)
+}
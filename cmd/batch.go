@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/batch"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/tracker"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultBatchRateLimit paces batches run without --rate-limit, matching the Etherscan
+// free tier of five requests per second with some headroom
+const defaultBatchRateLimit = 4
+
+var (
+	batchWorkers   int
+	batchOutputDir string
+	batchReport    string
+	batchStore     string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch FILE",
+	Short: "Export many addresses, one file each, with a batch report",
+	Long: `Reads addresses from FILE, either one per line or as CSV with address, label and chain
+columns, and exports each address to its own file in the output directory. Addresses are
+processed by a pool of workers sharing one rate limit. A failing address does not stop the
+others; every outcome is listed in the batch report, and the command fails at the end if any
+address failed. With --store, addresses are exported from a local store written by sync
+instead of being fetched.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if batchWorkers < 1 {
+			return fmt.Errorf("--workers must be at least 1")
+		}
+
+		entries, err := batch.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		options, err := exportOptions()
+		if err != nil {
+			return err
+		}
+
+		filter, err := trackFilter()
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(batchOutputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		report := batchReport
+		if report == "" {
+			report = filepath.Join(batchOutputDir, "batch-report.csv")
+		}
+
+		// Every worker waits for the same limiter, so the pool as a whole stays under the limit.
+		// Trackers given several API keys are paced per key by their key pool instead.
+		if limiter == nil {
+			limiter = etherscan.NewRateLimiter(defaultBatchRateLimit)
+		}
+
+		slog.Info("Exporting batch", "addresses", len(entries), "workers", batchWorkers, "output_dir", batchOutputDir)
+		start := time.Now()
+		results := batch.Run(entries, batchWorkers, func(entry batch.Entry) (map[string]interface{}, error) {
+			t, err := batchTracker(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			entryOptions := options
+			if entry.Label != "" {
+				entryOptions.Labels = map[string]string{strings.ToLower(entry.Address): entry.Label}
+				for address, label := range options.Labels {
+					entryOptions.Labels[address] = label
+				}
+			}
+			t.SetExportOptions(entryOptions)
+			t.SetFilter(filter)
+			if batchStore != "" {
+				t.ReplayFrom(archive.New(batchStore))
+			}
+
+			return t.ExportWallet(entry.Address, batchOutputFile(entry, options.Format))
+		})
+
+		if err := batch.WriteReport(report, results); err != nil {
+			return err
+		}
+
+		failed := batch.Failed(results)
+		total := 0
+		for _, result := range results {
+			total += result.Transactions
+		}
+		fmt.Printf("\n📋 Batch Summary:\n")
+		fmt.Printf("   Succeeded: %d\n", len(results)-failed)
+		fmt.Printf("   Failed: %d\n", failed)
+		fmt.Printf("   Total Transactions: %d\n", total)
+		fmt.Printf("   Duration: %s\n", time.Since(start).Round(time.Second))
+		fmt.Printf("   Report: %s\n", report)
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d addresses failed, see %s", failed, len(results), report)
+		}
+		slog.Info("Batch completed successfully")
+		return nil
+	},
+}
+
+// batchTracker creates a tracker for the chain of a batch entry, defaulting to --chain
+func batchTracker(entry batch.Entry) (*tracker.Tracker, error) {
+	name := entry.Chain
+	if name == "" {
+		name = chainName
+	}
+	chain, err := models.LookupChain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// An explicit --api-key applies to every chain
+	key := apiKey
+	if !apiKeyFlag {
+		key = settings.APIKey(chain.Name)
+	}
+
+	t := tracker.NewForChain(key, chain)
+	pace(t, key)
+	return t, nil
+}
+
+// batchOutputFile returns the output file of a batch entry, named after its address and,
+// when given, its chain
+func batchOutputFile(entry batch.Entry, format exporter.Format) string {
+	name := strings.ToLower(entry.Address)
+	if entry.Chain != "" {
+		name += "-" + strings.ToLower(entry.Chain)
+	}
+	return filepath.Join(batchOutputDir, name+exporter.Extension(format))
+}
+
+func init() {
+	batchCmd.Flags().IntVar(&batchWorkers, "workers", 4, "Number of addresses exported at the same time")
+	batchCmd.Flags().StringVar(&batchOutputDir, "output-dir", "batch", "Directory of the per-address output files")
+	batchCmd.Flags().StringVar(&batchReport, "report", "", "Batch report CSV file (default: batch-report.csv in the output directory)")
+	batchCmd.Flags().StringVar(&batchStore, "store", "", "Export from this local store written by sync instead of fetching")
+	addFilterFlags(batchCmd)
+	addExportFlags(batchCmd)
+	rootCmd.AddCommand(batchCmd)
+}
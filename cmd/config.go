@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/config"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/portfolio"
+	"crypto-acc-tracking/internal/tracker"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFile string
+	rateLimit  float64
+
+	// settings holds the configuration file and environment settings of the run
+	settings     = &config.Config{}
+	settingsFile string
+	apiKeyFlag   bool
+	limiter      *etherscan.RateLimiter
+
+	// keyPools holds the key pools of the run by key list, so every client given the same
+	// keys shares one pool and its per-key limits
+	keyPoolsMu sync.Mutex
+	keyPools   = make(map[string]*etherscan.KeyPool)
+	keyUsage   *etherscan.Usage
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration with secrets masked",
+	Long: `Prints the configuration file and CRYPTO_TRACKER_* environment settings in effect,
+with API keys masked. Command line flags override these settings.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		effective := *settings
+		if apiKeyFlag {
+			effective.APIKeys = map[string]string{config.DefaultKey: apiKey}
+		}
+		if rateLimit != 0 {
+			effective.RateLimit = rateLimit
+		}
+
+		data, err := effective.Masked().YAML()
+		if err != nil {
+			return err
+		}
+
+		source := settingsFile
+		if source == "" {
+			source = "none found"
+		}
+		fmt.Printf("# Config file: %s\n", source)
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Show today's request counts of the configured API keys",
+	Long: `Prints every configured API key, masked, with the requests sent with it today (UTC) and
+its daily quota, as recorded in the key usage file. Runs count requests there for keys given
+as a pool, a comma-separated list of keys in --api-key, apiKeys or CRYPTO_TRACKER_API_KEY.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lists := settings.APIKeys
+		if apiKeyFlag {
+			lists = map[string]string{config.DefaultKey: apiKey}
+		}
+
+		chains := make(map[string][]string)
+		var keys []string
+		for chain, list := range lists {
+			for _, key := range etherscan.ParseKeys(list) {
+				if _, ok := chains[key]; !ok {
+					keys = append(keys, key)
+				}
+				chains[key] = append(chains[key], chain)
+			}
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no API keys configured")
+		}
+		for _, key := range keys {
+			sort.Strings(chains[key])
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return strings.Join(chains[keys[i]], ",") < strings.Join(chains[keys[j]], ",")
+		})
+
+		usage, err := etherscan.OpenUsage(keyUsageFile())
+		if err != nil {
+			return err
+		}
+		limit := dailyLimit()
+
+		fmt.Printf("# Key usage file: %s\n", keyUsageFile())
+		fmt.Printf("%-40s %-14s %-20s %10s %10s\n", "KEY", "FINGERPRINT", "CHAINS", "TODAY", "QUOTA")
+		for _, key := range keys {
+			quota := "-"
+			if limit > 0 {
+				quota = fmt.Sprint(limit)
+			}
+			fmt.Printf("%-40s %-14s %-20s %10d %10s\n", config.Mask(key), etherscan.KeyID(key), strings.Join(chains[key], ","), usage.Count(key), quota)
+		}
+		return nil
+	},
+}
+
+// applyConfig loads the configuration file and environment settings and applies them to
+// the flags of cmd that were not given on the command line. Precedence, highest first:
+// flags, CRYPTO_TRACKER_* environment variables, the configuration file, built-in defaults.
+func applyConfig(cmd *cobra.Command) error {
+	filename, err := config.Find(configFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(filename)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ApplyEnv(os.Environ()); err != nil {
+		return err
+	}
+
+	for name, value := range cfg.Flags() {
+		if !settingApplies(name, cmd) {
+			continue
+		}
+
+		f := cmd.Flags().Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s setting %q: %w", name, value, err)
+		}
+	}
+
+	apiKeyFlag = cmd.Flags().Changed("api-key")
+	if !apiKeyFlag {
+		apiKey = cfg.APIKey(chainName)
+	}
+
+	if rateLimit < 0 {
+		return fmt.Errorf("--rate-limit must not be negative")
+	}
+	if rateLimit > 0 {
+		limiter = etherscan.NewRateLimiter(rateLimit)
+	}
+
+	settings = cfg
+	settingsFile = filename
+	return nil
+}
+
+// settingApplies reports whether a setting applies to the flag of the same name on cmd.
+// The output, format and store settings mean something else outside fetching and
+// exporting, e.g. --output is the holdings CSV of report and balance, --format of watch
+// only takes the formats it can append to, and --store is an optional source of report
+// and batch.
+func settingApplies(name string, cmd *cobra.Command) bool {
+	switch name {
+	case "output":
+		return cmd == rootCmd || cmd == syncCmd || cmd == exportCmd
+	case "format":
+		return cmd == rootCmd || cmd == exportCmd || cmd == batchCmd
+	case "store":
+		return cmd == rootCmd || cmd == syncCmd || cmd == exportCmd || cmd == serveCmd
+	}
+	return true
+}
+
+// loadPortfolio returns the portfolio of --portfolio, or the wallets of the configuration
+// file when neither --address nor --portfolio is given, with per-chain API keys and the
+// rate limit applied. It returns nil when a single --address is tracked.
+func loadPortfolio() (*portfolio.Portfolio, error) {
+	var p *portfolio.Portfolio
+	switch {
+	case portfolioFile != "":
+		loaded, err := portfolio.Load(portfolioFile)
+		if err != nil {
+			return nil, err
+		}
+		p = loaded
+	case address == "" && len(settings.Wallets) > 0:
+		p = &portfolio.Portfolio{
+			Name:    settings.Portfolio,
+			Wallets: settings.Wallets,
+		}
+		if p.Name == "" {
+			p.Name = "config"
+		}
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	pools := make(map[string]*etherscan.KeyPool)
+	for _, w := range p.Wallets {
+		chain, err := models.LookupChain(w.Chain)
+		if err != nil {
+			return nil, err
+		}
+		key := apiKey
+		if !apiKeyFlag {
+			key = settings.APIKey(w.Chain)
+		}
+		if pool := keyPool(key); pool != nil {
+			pools[chain.Name] = pool
+		}
+	}
+	p.SetKeyPools(pools)
+
+	// An explicit --api-key applies to every chain
+	if !apiKeyFlag {
+		keys := make(map[string]string)
+		for _, w := range p.Wallets {
+			keys[w.Chain] = settings.APIKey(w.Chain)
+		}
+		p.SetAPIKeys(keys)
+	}
+	if limiter != nil {
+		p.SetRateLimiter(limiter)
+	}
+
+	return p, nil
+}
+
+// pace applies the rate limits of the keys a tracker was created with: the key pool when
+// key lists several keys, otherwise the shared limiter, if any. An explicit --rate-limit
+// still caps a pool as a whole.
+func pace(t *tracker.Tracker, key string) {
+	if pool := keyPool(key); pool != nil {
+		t.SetKeyPool(pool)
+		if rateLimit > 0 {
+			t.SetRateLimiter(limiter)
+		}
+		return
+	}
+	if limiter != nil {
+		t.SetRateLimiter(limiter)
+	}
+}
+
+// keyPool returns the pool shared by every client given a comma-separated list of keys,
+// or nil for a single key
+func keyPool(list string) *etherscan.KeyPool {
+	keys := etherscan.ParseKeys(list)
+	if len(keys) < 2 {
+		return nil
+	}
+
+	keyPoolsMu.Lock()
+	defer keyPoolsMu.Unlock()
+
+	id := strings.Join(keys, ",")
+	if pool := keyPools[id]; pool != nil {
+		return pool
+	}
+
+	pool := etherscan.NewKeyPool(keys, settings.KeyPool.RateLimit)
+	pool.SetDailyLimit(dailyLimit())
+	if keyUsage == nil {
+		usage, err := etherscan.OpenUsage(keyUsageFile())
+		if err != nil {
+			slog.Warn("Not counting API key usage", "error", err)
+		}
+		keyUsage = usage
+	}
+	if keyUsage != nil {
+		pool.SetUsage(keyUsage)
+	}
+	keyPools[id] = pool
+
+	slog.Debug("Using API key pool", "keys", len(keys))
+	return pool
+}
+
+// dailyLimit returns the requests per day of each key of a pool, 0 for no limit
+func dailyLimit() int {
+	switch limit := settings.KeyPool.DailyLimit; {
+	case limit < 0:
+		return 0
+	case limit == 0:
+		return etherscan.DefaultDailyLimit
+	default:
+		return limit
+	}
+}
+
+// keyUsageFile returns the file counting the daily requests of pooled keys
+func keyUsageFile() string {
+	if settings.KeyPool.UsageFile != "" {
+		return settings.KeyPool.UsageFile
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "crypto-tracker", "key-usage.json")
+}
+
+// saveKeyUsage writes the request counts of the run's key pools to the usage file
+func saveKeyUsage() error {
+	keyPoolsMu.Lock()
+	defer keyPoolsMu.Unlock()
+	if keyUsage == nil {
+		return nil
+	}
+	return keyUsage.Save()
+}
+
+func init() {
+	configCmd.AddCommand(configKeysCmd)
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
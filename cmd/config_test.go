@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSettingApplies(t *testing.T) {
+	tests := []struct {
+		setting string
+		cmd     *cobra.Command
+		want    bool
+	}{
+		{"format", rootCmd, true},
+		{"format", exportCmd, true},
+		{"format", batchCmd, true},
+		{"format", watchCmd, false},
+		{"output", rootCmd, true},
+		{"output", watchCmd, false},
+		{"store", serveCmd, true},
+		{"store", batchCmd, false},
+		{"chain", watchCmd, true},
+	}
+	for _, tt := range tests {
+		if got := settingApplies(tt.setting, tt.cmd); got != tt.want {
+			t.Errorf("settingApplies(%s, %s) = %v, want %v", tt.setting, tt.cmd.Name(), got, tt.want)
+		}
+	}
+}
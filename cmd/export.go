@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Aliases: []string{"reprocess"},
+	Short:   "Render wallet history from the local store in any format",
+	Long: `Renders the history of a wallet or portfolio saved by sync, or archived with --archive,
+running the current processing code without any network calls. Filters are applied to the
+stored history, so one sync serves any number of filtered exports.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(storeDir); err != nil {
+			return fmt.Errorf("failed to open store (run sync first): %w", err)
+		}
+
+		options, err := exportOptions()
+		if err != nil {
+			return err
+		}
+
+		filter, err := trackFilter()
+		if err != nil {
+			return err
+		}
+
+		slog.Info("Exporting from store", "store", storeDir)
+		return track("", options, filter, storeDir, true)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&outputFile, "output", "o", "transactions.csv", "Output file path")
+	addStoreFlag(exportCmd)
+
+	// Archives written with --archive are stores, reprocess --archive DIR predates export
+	exportCmd.Flags().StringVar(&storeDir, "archive", defaultStoreDir, "Directory holding the archived responses")
+	exportCmd.Flags().MarkDeprecated("archive", "use --store instead")
+
+	addFilterFlags(exportCmd)
+	addExportFlags(exportCmd)
+
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/notify"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage watch notifications",
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample notification to every sink of the config file",
+	Long: `Sends a made-up transaction to every notification sink of the config file, with the
+configured retries, so webhook endpoints, signatures and commands can be checked before
+relying on them in watch. Failed deliveries are written to the dead-letter file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifier, err := notify.New(settings.Notify)
+		if err != nil {
+			return err
+		}
+		sinks := notifier.Sinks()
+		if len(sinks) == 0 {
+			return fmt.Errorf("no notification sinks in the config file")
+		}
+
+		wallet := strings.ToLower(address)
+		if wallet == "" {
+			wallet = "0x0000000000000000000000000000000000000000"
+		}
+		notification := &notify.Notification{
+			Rule:    "test",
+			Address: wallet,
+			Chain:   chainName,
+			SentAt:  time.Now().UTC(),
+			Transaction: &models.Transaction{
+				Hash:            "0x" + strings.Repeat("0", 64),
+				BlockNumber:     "0",
+				DateTime:        time.Now().UTC(),
+				Chain:           chainName,
+				FromAddress:     wallet,
+				ToAddress:       "0x000000000000000000000000000000000000dead",
+				TransactionType: models.ETHTransfer,
+				AssetSymbol:     "ETH",
+				Value:           big.NewInt(1000000000000000000),
+				ValueFormatted:  "1",
+				Status:          "Success",
+				Direction:       models.DirectionOut,
+			},
+		}
+
+		failed := 0
+		for _, sink := range sinks {
+			delivered, err := notifier.Deliver(sink, notification)
+			if err != nil {
+				return err
+			}
+			if !delivered {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d sinks failed", failed, len(sinks))
+		}
+
+		fmt.Printf("\n🎉 All %d sinks notified\n", len(sinks))
+		return nil
+	},
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyTestCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
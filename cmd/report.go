@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/report"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	atDate       string
+	atBlock      int
+	pricesFile   string
+	currency     string
+	includeNFTs  bool
+	verify       bool
+	reportFile   string
+	historyStore string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports from wallet history",
+	Long: `Generates reports from the history of a wallet or portfolio, fetched from Etherscan
+or read with --store from the local store written by sync.`,
+}
+
+var holdingsCmd = &cobra.Command{
+	Use:   "holdings",
+	Short: "Show wallet holdings at a date or block",
+	Long: `Replays the processed transaction history up to a date or block number and
+reports per-asset quantities, optionally valued in fiat and with the NFT inventory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cutoff, err := parseCutoff(atDate, atBlock)
+		if err != nil {
+			return err
+		}
+
+		if verify && (portfolioFile != "" || address == "") {
+			return fmt.Errorf("--verify is only supported for a single --address")
+		}
+
+		transactions, owned, err := loadHistory()
+		if err != nil {
+			return err
+		}
+
+		return showHoldings(transactions, owned, cutoff)
+	},
+}
+
+// showHoldings prints the holdings at the cutoff, valued and verified as the flags ask,
+// and writes them to the --output CSV file if one is given
+func showHoldings(transactions []*models.Transaction, owned map[string]bool, cutoff report.Cutoff) error {
+	snapshot := report.ComputeHoldings(transactions, owned, cutoff)
+
+	if pricesFile != "" {
+		prices, err := report.LoadPrices(pricesFile)
+		if err != nil {
+			return err
+		}
+		snapshot.ApplyPrices(prices, currency)
+	}
+
+	var checks []report.BalanceCheck
+	if verify {
+		chain, err := models.LookupChain(chainName)
+		if err != nil {
+			return err
+		}
+		client := etherscan.NewForChain(apiKey, chain)
+		if pool := keyPool(apiKey); pool != nil {
+			client.SetKeyPool(pool)
+		}
+
+		if snapshot.Cutoff.Block == 0 {
+			block, err := client.GetBlockNumberByTime(snapshot.Cutoff.Time.Unix())
+			if err != nil {
+				return fmt.Errorf("failed to resolve block for %s: %w", atDate, err)
+			}
+			snapshot.Cutoff.Block = block
+		}
+		checks = snapshot.Verify(client, strings.ToLower(address))
+	}
+
+	snapshot.Print(checks, includeNFTs)
+
+	if reportFile != "" {
+		if err := snapshot.WriteCSV(reportFile, includeNFTs); err != nil {
+			return fmt.Errorf("failed to export holdings: %w", err)
+		}
+		fmt.Printf("\n💾 Holdings exported to CSV: %s\n", reportFile)
+	}
+
+	return nil
+}
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize wallet history by period, type, direction and asset",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		transactions, owned, err := loadHistory()
+		if err != nil {
+			return err
+		}
+
+		counter := exporter.NewSummaryCounter()
+		var first, last time.Time
+		for _, tx := range transactions {
+			counter.Add(tx)
+			if first.IsZero() || tx.DateTime.Before(first) {
+				first = tx.DateTime
+			}
+			if tx.DateTime.After(last) {
+				last = tx.DateTime
+			}
+		}
+
+		summary := counter.Summary("")
+		fmt.Printf("\n📈 Wallet Summary: %d addresses\n", len(owned))
+		if len(transactions) > 0 {
+			fmt.Printf("   Period: %s to %s\n", first.UTC().Format("2006-01-02"), last.UTC().Format("2006-01-02"))
+		}
+		fmt.Printf("   Total Transactions: %d\n", summary["total_transactions"])
+		fmt.Printf("   Unique Assets: %d\n", summary["unique_assets"])
+
+		if typeCounts, ok := summary["transaction_types"].(map[models.TransactionType]int); ok && len(typeCounts) > 0 {
+			fmt.Printf("\n📊 Transaction Types:\n")
+			for _, txType := range sortedKeys(typeCounts) {
+				fmt.Printf("   %s: %d\n", txType, typeCounts[txType])
+			}
+		}
+
+		if directionCounts, ok := summary["directions"].(map[models.Direction]int); ok && len(directionCounts) > 0 {
+			fmt.Printf("\n🔁 Flow Directions:\n")
+			for _, direction := range sortedKeys(directionCounts) {
+				label := direction
+				if label == "" {
+					label = "Other"
+				}
+				fmt.Printf("   %s: %d\n", label, directionCounts[direction])
+			}
+		}
+
+		return nil
+	},
+}
+
+// sortedKeys returns the keys of counts in alphabetical order, so summaries print the same
+// way on every run
+func sortedKeys[K ~string](counts map[K]int) []K {
+	keys := make([]K, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// loadHistory returns the processed history of the selected wallet or portfolio and its
+// owned addresses, read from the local store when --store is given and fetched otherwise
+func loadHistory() ([]*models.Transaction, map[string]bool, error) {
+	if err := requireWallet(); err != nil {
+		return nil, nil, err
+	}
+
+	var store *archive.Archive
+	if historyStore != "" {
+		if _, err := os.Stat(historyStore); err != nil {
+			return nil, nil, fmt.Errorf("failed to open store (run sync first): %w", err)
+		}
+		store = archive.New(historyStore)
+	}
+
+	p, err := loadPortfolio()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p != nil {
+		if store != nil {
+			p.ReplayFrom(store)
+		}
+		transactions, err := p.Fetch(apiKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return transactions, p.Owned(), nil
+	}
+
+	t, err := walletTracker()
+	if err != nil {
+		return nil, nil, err
+	}
+	if store != nil {
+		t.ReplayFrom(store)
+	}
+	transactions, err := t.FetchTransactions(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned := map[string]bool{strings.ToLower(address): true}
+	processor.New().AssignDirections(transactions, owned)
+	return transactions, owned, nil
+}
+
+// addHistoryFlags registers the flags choosing where report commands read history from
+func addHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&historyStore, "store", "", "Read wallet history from this local store (written by sync) instead of Etherscan")
+}
+
+// addHoldingsFlags registers the flags valuing and writing holdings on a command
+func addHoldingsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&reportFile, "output", "o", "", "Optional CSV file path for the holdings")
+	cmd.Flags().StringVar(&pricesFile, "prices", "", "JSON file mapping asset symbols or contract addresses to unit prices")
+	cmd.Flags().StringVar(&currency, "currency", "USD", "Fiat currency label for valuations")
+	cmd.Flags().BoolVar(&includeNFTs, "nfts", false, "Include the NFT inventory by contract and token ID")
+}
+
+// parseCutoff builds a report cutoff from a date (end of day UTC or RFC 3339) and/or a block
+func parseCutoff(date string, block int) (report.Cutoff, error) {
+	cutoff := report.Cutoff{Block: block}
+
+	if date != "" {
+		if day, err := time.Parse("2006-01-02", date); err == nil {
+			cutoff.Time = day.Add(24*time.Hour - time.Second)
+		} else if ts, err := time.Parse(time.RFC3339, date); err == nil {
+			cutoff.Time = ts.UTC()
+		} else {
+			return cutoff, fmt.Errorf("invalid date %q: expected YYYY-MM-DD or RFC 3339", date)
+		}
+	}
+
+	if cutoff.Block == 0 && cutoff.Time.IsZero() {
+		return cutoff, fmt.Errorf("either --at-date or --at-block is required")
+	}
+
+	return cutoff, nil
+}
+
+func init() {
+	addHistoryFlags(holdingsCmd)
+	addHoldingsFlags(holdingsCmd)
+	holdingsCmd.Flags().StringVar(&atDate, "at-date", "", "Report holdings at the end of this date (YYYY-MM-DD, UTC) or at an RFC 3339 time")
+	holdingsCmd.Flags().IntVar(&atBlock, "at-block", 0, "Report holdings at this block number")
+	holdingsCmd.Flags().BoolVar(&verify, "verify", false, "Cross-check balances against Etherscan historical balances (requires an API Pro key)")
+
+	addHistoryFlags(summaryCmd)
+
+	reportCmd.AddCommand(holdingsCmd)
+	reportCmd.AddCommand(summaryCmd)
+	rootCmd.AddCommand(reportCmd)
+}
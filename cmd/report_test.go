@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"testing"
+)
+
+func TestSortedKeys(t *testing.T) {
+	counts := map[models.Direction]int{models.DirectionOut: 2, "": 1, models.DirectionIn: 3, models.DirectionSelf: 1}
+	got := sortedKeys(counts)
+	want := []models.Direction{"", models.DirectionIn, models.DirectionOut, models.DirectionSelf}
+	if len(got) != len(want) {
+		t.Fatalf("sortedKeys() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortedKeys() = %v, want %v", got, want)
+		}
+	}
+}
@@ -1,40 +1,378 @@
 package cmd
 
 import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/logging"
+	"crypto-acc-tracking/internal/metrics"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"crypto-acc-tracking/internal/processor"
 	"crypto-acc-tracking/internal/tracker"
+	"crypto-acc-tracking/internal/version"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	address string
-	apiKey  string
-	output  string
+	address       string
+	apiKey        string
+	outputFile    string
+	chainName     string
+	portfolioFile string
+	format        string
+	labelsFile    string
+	csvSchemaFile string
+	sortOrder     string
+	archiveDir    string
+	noClobber     bool
+	timestamped   bool
+	rotate        int
+	splitBy       string
+	splitTemplate string
+	fromDate      string
+	toDate        string
+	startBlock    int
+	endBlock      int
+	txTypes       string
+	token         string
+	minValue      string
+	txStatus      string
+	counterparty  string
+	metricsFile   string
+	logFormat     string
+	progressMode  string
+	quiet         bool
+	verbose       bool
+	accounts      = exporter.DefaultAccountTemplates()
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "crypto-tracker",
 	Short: "Ethereum wallet transaction tracker",
-	Long:  `A CLI tool to track and export Ethereum wallet transactions to CSV format.`,
+	Long: `A CLI tool to track and export Ethereum wallet transactions.
+
+Use sync to fetch wallet history into a local store and export to render it in any format,
+or run without a command to fetch and export in one go:
+
+  crypto-tracker -a 0xa39b... -o wallet.csv`,
+	Version: version.Version,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if address == "" {
-			return fmt.Errorf("ethereum address is required")
+		if err := requireWallet(); err != nil {
+			return err
+		}
+
+		options, err := exportOptions()
+		if err != nil {
+			return err
 		}
 
-		t := tracker.New(apiKey)
-		return t.TrackWallet(address, output)
+		filter, err := trackFilter()
+		if err != nil {
+			return err
+		}
+
+		return track(apiKey, options, filter, archiveDir, false)
 	},
 }
 
+// track exports the wallet or portfolio selected by the flags. With an archive directory,
+// raw responses are saved to it, or with replay read back from it without network calls.
+func track(apiKey string, options exporter.Options, filter processor.Filter, archiveDir string, replay bool) error {
+	if err := requireWallet(); err != nil {
+		return err
+	}
+
+	// Fail before fetching anything when the output may not be overwritten
+	if options.SplitBy == exporter.PartitionNone {
+		if _, err := options.Naming.Resolve(outputFile, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	var a *archive.Archive
+	if archiveDir != "" {
+		a = archive.New(archiveDir)
+	}
+
+	p, err := loadPortfolio()
+	if err != nil {
+		return err
+	}
+	if p != nil {
+		p.SetFilter(filter)
+		if replay {
+			p.ReplayFrom(a)
+		} else if a != nil {
+			p.ArchiveTo(a)
+		}
+		return p.Track(apiKey, outputFile, options)
+	}
+
+	t, err := walletTracker()
+	if err != nil {
+		return err
+	}
+	t.SetExportOptions(options)
+	t.SetFilter(filter)
+	if replay {
+		t.ReplayFrom(a)
+	} else if a != nil {
+		t.ArchiveTo(a)
+	}
+	return t.TrackWallet(address, outputFile)
+}
+
+// requireWallet checks that exactly one of --address and --portfolio is set, or that the
+// configuration file lists wallets
+func requireWallet() error {
+	if address != "" && portfolioFile != "" {
+		return fmt.Errorf("--address and --portfolio cannot be used together")
+	}
+	if address == "" && portfolioFile == "" && len(settings.Wallets) == 0 {
+		return fmt.Errorf("either --address or --portfolio is required")
+	}
+	return nil
+}
+
+// walletTracker creates a tracker for the chain selected by --chain
+func walletTracker() (*tracker.Tracker, error) {
+	chain, err := models.LookupChain(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tracker.NewForChain(apiKey, chain)
+	pace(t, apiKey)
+	return t, nil
+}
+
+// exportOptions builds the export options from the output flags
+func exportOptions() (exporter.Options, error) {
+	exportFormat, err := exporter.ParseFormat(format)
+	if err != nil {
+		return exporter.Options{}, err
+	}
+
+	order, err := processor.ParseSortOrder(sortOrder)
+	if err != nil {
+		return exporter.Options{}, err
+	}
+
+	split, err := exporter.ParsePartition(splitBy)
+	if err != nil {
+		return exporter.Options{}, err
+	}
+	if split != exporter.PartitionNone {
+		if err := exporter.ValidateSplitTemplate(splitTemplate); err != nil {
+			return exporter.Options{}, err
+		}
+	}
+
+	if rotate < 0 {
+		return exporter.Options{}, fmt.Errorf("--rotate must not be negative")
+	}
+
+	options := exporter.Options{
+		Format:   exportFormat,
+		Accounts: accounts,
+		Order:    order,
+		Naming: output.Naming{
+			NoClobber: noClobber,
+			Timestamp: timestamped,
+			Rotate:    rotate,
+		},
+		SplitBy: split,
+	}
+	if split != exporter.PartitionNone {
+		options.SplitTemplate = splitTemplate
+	}
+
+	if len(settings.Labels) > 0 {
+		options.Labels = make(map[string]string, len(settings.Labels))
+		for address, label := range settings.Labels {
+			options.Labels[address] = label
+		}
+	}
+	if labelsFile != "" {
+		labels, err := loadLabels(labelsFile)
+		if err != nil {
+			return options, err
+		}
+		if options.Labels == nil {
+			options.Labels = labels
+		}
+		for address, label := range labels {
+			options.Labels[address] = label
+		}
+	}
+
+	if csvSchemaFile != "" {
+		schema, err := exporter.LoadCSVSchema(csvSchemaFile)
+		if err != nil {
+			return options, err
+		}
+		options.CSV = schema
+	}
+
+	return options, nil
+}
+
+// trackFilter builds the transaction filter from the filter flags
+func trackFilter() (processor.Filter, error) {
+	return processor.ParseFilter(processor.FilterSpec{
+		FromDate:     fromDate,
+		ToDate:       toDate,
+		StartBlock:   startBlock,
+		EndBlock:     endBlock,
+		Types:        txTypes,
+		Token:        token,
+		MinValue:     minValue,
+		Status:       txStatus,
+		Counterparty: counterparty,
+	})
+}
+
+// loadLabels reads a JSON object mapping addresses to human-readable labels
+func loadLabels(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read labels file: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse labels file: %w", err)
+	}
+
+	labels := make(map[string]string, len(raw))
+	for address, label := range raw {
+		labels[strings.ToLower(address)] = label
+	}
+	return labels, nil
+}
+
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if saveErr := saveKeyUsage(); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	if metricsFile != "" {
+		if dumpErr := writeMetrics(metricsFile); dumpErr != nil && err == nil {
+			err = dumpErr
+		}
+	}
+	return err
+}
+
+// setupLogging configures the logger and progress reporter from the logging flags
+func setupLogging() error {
+	if quiet && verbose {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+
+	format, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		return err
+	}
+	mode, err := logging.ParseProgressMode(progressMode)
+	if err != nil {
+		return err
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case quiet:
+		level = slog.LevelWarn
+		mode = logging.ProgressNone
+	case verbose:
+		level = slog.LevelDebug
+	}
+
+	logging.Setup(logging.Options{Format: format, Level: level, Progress: mode})
+	return nil
+}
+
+// writeMetrics dumps the metrics of the run to a file, or to stderr for "-". Files are
+// replaced atomically, so a textfile collector never reads a partial dump.
+func writeMetrics(filename string) error {
+	if filename == "-" {
+		return metrics.Write(os.Stderr)
+	}
+
+	file, err := output.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	if err := metrics.Write(file); err != nil {
+		file.Abort()
+		return err
+	}
+	return file.Commit()
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&address, "address", "a", "", "Ethereum wallet address to track (required)")
-	rootCmd.Flags().StringVarP(&apiKey, "api-key", "k", "", "Etherscan API key (optional but recommended for higher rate limits)")
-	rootCmd.Flags().StringVarP(&output, "output", "o", "transactions.csv", "Output CSV file path")
+	// Logging is set up first, so loading settings can already log. Settings from the config
+	// file and environment then fill in flags not given on the command line.
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		return applyConfig(cmd)
+	}
+
+	// Wallet selection is shared by every command
+	rootCmd.PersistentFlags().StringVarP(&address, "address", "a", "", "Ethereum wallet address (required unless --portfolio is set)")
+	rootCmd.PersistentFlags().StringVarP(&apiKey, "api-key", "k", "", "Etherscan API key, or a comma-separated list of keys to spread requests over (optional but recommended for higher rate limits)")
+	rootCmd.PersistentFlags().StringVarP(&chainName, "chain", "c", models.DefaultChain.Name, "Chain of the wallet (ethereum, arbitrum, optimism, base, polygon, bsc, avalanche)")
+	rootCmd.PersistentFlags().StringVarP(&portfolioFile, "portfolio", "p", "", "Portfolio JSON file listing owned wallets to track as one entity")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default: crypto-tracker/config.yaml in $XDG_CONFIG_HOME or $XDG_CONFIG_DIRS)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", string(logging.FormatText), "Format of the log on stderr: text or json")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log warnings and errors, without progress")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Also log debug details, such as every page fetched")
+	rootCmd.PersistentFlags().StringVar(&progressMode, "progress", string(logging.ProgressAuto), "Progress on stderr: bar, json events, none, or auto (a bar on a terminal, json otherwise)")
+	rootCmd.PersistentFlags().StringVar(&metricsFile, "metrics-file", "", "Write Prometheus metrics of the run to this file when it ends, or to stderr for \"-\"")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "Etherscan requests per second shared by all wallets (default: fixed delays between requests)")
+
+	// Running without a command fetches and exports in one go, as sync followed by export
+	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "transactions.csv", "Output file path")
+	rootCmd.Flags().StringVar(&archiveDir, "archive", "", "Directory to archive every raw Etherscan response in, for audit and offline reprocessing")
+	addFilterFlags(rootCmd)
+	addExportFlags(rootCmd)
+}
+
+// addFilterFlags registers the flags selecting which transactions are tracked
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&fromDate, "from-date", "", "Only include transactions on or after this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&toDate, "to-date", "", "Only include transactions up to this date, inclusive (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().IntVar(&startBlock, "start-block", 0, "Only include transactions from this block")
+	cmd.Flags().IntVar(&endBlock, "end-block", 0, "Only include transactions up to this block (default: latest)")
+	cmd.Flags().StringVar(&txTypes, "types", "", "Comma-separated transaction types to include: eth, erc20, erc721, erc1155, contract, internal (default: all)")
+	cmd.Flags().StringVar(&token, "token", "", "Only include transfers of this token contract")
+	cmd.Flags().StringVar(&minValue, "min-value", "", "Only include transfers of at least this amount, in asset units (e.g. 0.5)")
+	cmd.Flags().StringVar(&txStatus, "status", "", "Only include success or failed transactions")
+	cmd.Flags().StringVar(&counterparty, "counterparty", "", "Only include transactions to or from this address")
+}
 
-	rootCmd.MarkFlagRequired("address")
+// addExportFlags registers the flags controlling the output format on a command
+func addExportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Output format: csv, json, ndjson, parquet, sqlite, xlsx, beancount, ledger or hledger (default: inferred from the output file extension)")
+	cmd.Flags().StringVar(&sortOrder, "order", string(processor.Descending), "Row order: asc (oldest first) or desc (newest first), by block, transaction index and log index")
+	cmd.Flags().StringVar(&labelsFile, "labels", "", "JSON file mapping addresses to labels used in account names")
+	cmd.Flags().StringVar(&csvSchemaFile, "csv-schema", "", "JSON file selecting CSV columns, delimiter, decimal separator, date format and time zone")
+	cmd.Flags().StringVar(&splitBy, "split-by", "", "Write one file per year, month, asset, type or counterparty instead of a single file")
+	cmd.Flags().StringVar(&splitTemplate, "split-template", exporter.DefaultSplitTemplate, "File name template of split exports, using {name}, {key} and {ext}, relative to the output directory")
+	cmd.Flags().BoolVar(&noClobber, "no-clobber", false, "Refuse to overwrite an existing output file")
+	cmd.Flags().BoolVar(&timestamped, "timestamp", false, "Insert the UTC run time into the output file name, e.g. transactions-20240101T120000Z.csv")
+	cmd.Flags().IntVar(&rotate, "rotate", 0, "Keep this many previous exports as NAME.1.EXT, NAME.2.EXT, ... (0 overwrites)")
+	cmd.Flags().StringVar(&accounts.Asset, "asset-account", accounts.Asset, "Account template for wallet assets")
+	cmd.Flags().StringVar(&accounts.Income, "income-account", accounts.Income, "Account template for incoming transfers")
+	cmd.Flags().StringVar(&accounts.Expense, "expense-account", accounts.Expense, "Account template for outgoing transfers")
+	cmd.Flags().StringVar(&accounts.Fee, "fee-account", accounts.Fee, "Account template for gas fees")
 }
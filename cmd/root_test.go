@@ -0,0 +1,10 @@
+package cmd
+
+import "testing"
+
+func TestRootRequiresWallet(t *testing.T) {
+	err := rootCmd.RunE(rootCmd, nil)
+	if err == nil || err.Error() != "either --address or --portfolio is required" {
+		t.Errorf("running without a wallet returned %v, want the missing wallet error", err)
+	}
+}
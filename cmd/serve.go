@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"crypto-acc-tracking/api/trackerpb"
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/logging"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/server"
+	"crypto-acc-tracking/internal/tracker"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	serveListen     string
+	serveGRPCListen string
+	serveWorkers    int
+	serveQueue      int
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve tracking jobs, transactions and exports over a REST API",
+	Long: `Starts an HTTP server other services can use instead of the CLI. POST /api/v1/jobs starts a
+job fetching the history of an address into the local store; jobs run on a pool of workers
+sharing one rate limit and can be polled and cancelled. Once a job has succeeded, its
+transactions are served as JSON with filtering and pagination, and as downloads in any
+export format.
+
+With --grpc-listen, the Tracker gRPC service of api/trackerpb is served as well: TrackWallet
+streams transactions as their pages are processed, GetSummary and GetBalances answer in one
+call. It fetches with the same rate limit and store as the jobs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveWorkers < 1 {
+			return fmt.Errorf("--workers must be at least 1")
+		}
+		if serveQueue < 1 {
+			return fmt.Errorf("--queue must be at least 1")
+		}
+
+		// Jobs run concurrently and for other services, so they log without progress
+		logging.SetProgress(nil)
+
+		// Every worker waits for the same limiter, so the pool as a whole stays under the limit.
+		// Trackers given several API keys are paced per key by their key pool instead.
+		if limiter == nil {
+			limiter = etherscan.NewRateLimiter(defaultBatchRateLimit)
+		}
+
+		store := archive.New(storeDir)
+		newTracker := func(chain models.Chain) *tracker.Tracker {
+			// An explicit --api-key applies to every chain
+			key := apiKey
+			if !apiKeyFlag {
+				key = settings.APIKey(chain.Name)
+			}
+			t := tracker.NewForChain(key, chain)
+			pace(t, key)
+			t.ArchiveTo(store)
+			return t
+		}
+		jobs := server.NewManager(newTracker, serveWorkers, serveQueue)
+
+		httpServer := &http.Server{
+			Addr:              serveListen,
+			Handler:           server.New(jobs, store),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		errs := make(chan error, 2)
+		go func() {
+			errs <- httpServer.ListenAndServe()
+		}()
+		slog.Info("Serving", "listen", serveListen, "workers", serveWorkers, "store", storeDir)
+
+		var grpcServer *grpc.Server
+		if serveGRPCListen != "" {
+			listener, err := net.Listen("tcp", serveGRPCListen)
+			if err != nil {
+				httpServer.Close()
+				jobs.Shutdown()
+				return fmt.Errorf("failed to listen for gRPC: %w", err)
+			}
+			grpcServer = grpc.NewServer()
+			trackerpb.RegisterTrackerServer(grpcServer, server.NewGRPCService(newTracker))
+			go func() {
+				errs <- grpcServer.Serve(listener)
+			}()
+			slog.Info("Serving gRPC", "listen", serveGRPCListen)
+		}
+
+		select {
+		case err := <-errs:
+			httpServer.Close()
+			if grpcServer != nil {
+				grpcServer.Stop()
+			}
+			jobs.Shutdown()
+			return fmt.Errorf("failed to serve: %w", err)
+		case <-ctx.Done():
+		}
+
+		slog.Info("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if grpcServer != nil {
+			// Streams in flight end with the cancellation of their context
+			grpcServer.Stop()
+		}
+		jobs.Shutdown()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to shut down: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveGRPCListen, "grpc-listen", "", "Address to serve the gRPC API on (disabled when empty)")
+	serveCmd.Flags().IntVar(&serveWorkers, "workers", 2, "Number of jobs run at the same time")
+	serveCmd.Flags().IntVar(&serveQueue, "queue", 100, "Number of jobs that can wait for a worker")
+	addStoreFlag(serveCmd)
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultStoreDir is where sync keeps fetched wallet history unless --store is given
+const defaultStoreDir = "crypto-tracker-store"
+
+var storeDir string
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Fetch wallet history into the local store",
+	Long: `Fetches the complete history of a wallet or portfolio from Etherscan and saves the raw
+responses in the local store, from which export, report and balance work without network calls.
+Re-running sync refreshes the store.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireWallet(); err != nil {
+			return err
+		}
+
+		store := archive.New(storeDir)
+		slog.Info("Syncing into store", "store", storeDir)
+
+		p, err := loadPortfolio()
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			p.ArchiveTo(store)
+
+			count, err := p.Sync(apiKey)
+			if err != nil {
+				return err
+			}
+			slog.Info("Synced transactions", "portfolio", p.Name, "count", count)
+			return nil
+		}
+
+		t, err := walletTracker()
+		if err != nil {
+			return err
+		}
+		t.ArchiveTo(store)
+
+		count, err := t.Sync(address)
+		if err != nil {
+			return err
+		}
+		slog.Info("Synced transactions", "address", strings.ToLower(address), "count", count)
+		return nil
+	},
+}
+
+// addStoreFlag registers the flag selecting the local store on a command
+func addStoreFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&storeDir, "store", defaultStoreDir, "Directory of the local store written by sync")
+}
+
+func init() {
+	addStoreFlag(syncCmd)
+	rootCmd.AddCommand(syncCmd)
+}
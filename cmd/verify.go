@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"crypto-acc-tracking/internal/manifest"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var manifestFile string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <file>",
+	Short: "Check an export against its manifest",
+	Long: `Recomputes the SHA-256 and size of an export file, and the row count of CSV files,
+and compares them with the manifest written alongside it (<file>.manifest.json).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+		if manifestFile == "" {
+			manifestFile = manifest.PathFor(filename)
+		}
+
+		m, err := manifest.Load(manifestFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🔎 Verifying %s against %s\n", filename, manifestFile)
+		fmt.Printf("   Generated by version %s for %d addresses\n", m.ToolVersion, len(m.Addresses))
+		for _, chain := range m.Chains {
+			r := m.BlockRanges[chain]
+			fmt.Printf("   %s blocks %d to %d\n", chain, r.From, r.To)
+		}
+
+		check, err := m.Verify(manifestFile, filename)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("   SHA-256: %s\n", check.SHA256)
+		if check.Rows >= 0 {
+			fmt.Printf("   Rows: %d\n", check.Rows)
+		}
+
+		if !check.OK() {
+			for _, problem := range check.Problems {
+				fmt.Printf("❌ %s\n", problem)
+			}
+			return fmt.Errorf("%s does not match its manifest", filename)
+		}
+
+		fmt.Printf("✅ %s matches its manifest\n", filename)
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&manifestFile, "manifest", "m", "", "Manifest file (default: <file>.manifest.json)")
+
+	rootCmd.AddCommand(verifyCmd)
+}
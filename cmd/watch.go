@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/logging"
+	"crypto-acc-tracking/internal/metrics"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/notify"
+	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/tracker"
+	"crypto-acc-tracking/internal/watch"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval   time.Duration
+	watchReorgDepth int
+	watchOutput     string
+	watchFormat     string
+	watchMetrics    string
+	watchCursor     string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow new blocks and print new transactions of a wallet",
+	Long: `Polls Etherscan for transactions of a wallet in blocks after the current one, or from
+--start-block, and prints each new transaction as it is found. Every poll re-checks the last
+--reorg-depth blocks, so transactions dropped by a chain reorganization are reported as removed.
+With --output, transactions are appended to a CSV, NDJSON or SQLite file once they are deeper
+than the reorg depth, so the file never holds a transaction that was later removed.
+New transactions matching the notification rules of the config file are sent to its sinks.
+A newContract rule only fires for contracts the wallet never called before: the contracts it
+called before the first watched block are loaded from Etherscan at startup.
+With --cursor, the first unconfirmed block is saved after every poll and a restarted watch
+resumes from it instead of the current block, taking precedence over --start-block.
+With --metrics-listen, Prometheus metrics are served on /metrics. Runs until interrupted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if address == "" {
+			return fmt.Errorf("watch supports a single --address")
+		}
+		if watchInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if watchReorgDepth < 0 {
+			return fmt.Errorf("--reorg-depth must not be negative")
+		}
+
+		// Every poll is a short fetch, so progress would only repeat itself
+		logging.SetProgress(nil)
+
+		filter, err := trackFilter()
+		if err != nil {
+			return err
+		}
+
+		options := exporter.Options{Owned: map[string]bool{strings.ToLower(address): true}}
+		if watchOutput != "" {
+			exportFormat, err := exporter.ParseFormat(watchFormat)
+			if err != nil {
+				return err
+			}
+			if exportFormat == "" {
+				exportFormat = exporter.InferFormat(watchOutput)
+			}
+			if !exporter.Appendable(exportFormat) {
+				return fmt.Errorf("watch cannot append to %s output, use csv, ndjson or sqlite", exportFormat)
+			}
+			options.Format = exportFormat
+		}
+
+		notifier, err := notify.New(settings.Notify)
+		if err != nil {
+			return err
+		}
+
+		t, err := walletTracker()
+		if err != nil {
+			return err
+		}
+
+		w := watch.New(t, address, watch.Options{
+			Interval:   watchInterval,
+			ReorgDepth: watchReorgDepth,
+			StartBlock: startBlock,
+			CursorFile: watchCursor,
+		})
+
+		if notifier.TracksNewContracts() {
+			start, err := w.Start()
+			if err != nil {
+				return err
+			}
+			if err := seedContracts(t, notifier, start); err != nil {
+				return err
+			}
+		}
+		t.SetFilter(filter)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if watchMetrics != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler())
+			metricsServer := &http.Server{
+				Addr:              watchMetrics,
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					slog.Warn("Metrics server stopped", "error", err)
+				}
+			}()
+			defer metricsServer.Close()
+			slog.Info("Serving metrics", "listen", watchMetrics)
+		}
+
+		err = w.Run(ctx, func(events []watch.Event) error {
+			var confirmed []*models.Transaction
+			for _, event := range events {
+				switch event.Kind {
+				case watch.Added:
+					printWatched("🆕", event.Transaction)
+					if err := notifier.Check(address, event.Transaction); err != nil {
+						return err
+					}
+				case watch.Removed:
+					printWatched("↩️ ", event.Transaction)
+				case watch.Confirmed:
+					confirmed = append(confirmed, event.Transaction)
+				}
+			}
+
+			if watchOutput == "" || len(confirmed) == 0 {
+				return nil
+			}
+			if err := exporter.Append(watchOutput, confirmed, options); err != nil {
+				return fmt.Errorf("failed to append to %s: %w", watchOutput, err)
+			}
+			slog.Info("Appended confirmed transactions", "count", len(confirmed), "file", watchOutput)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if w.Pending() > 0 && watchOutput != "" {
+			slog.Warn("Stopped with unconfirmed transactions not written", "count", w.Pending(), "file", watchOutput)
+		}
+		return nil
+	},
+}
+
+// seedContracts records the contracts the wallet called before the start block, so
+// newContract rules only notify for contracts it never called before. It replaces the
+// filter of the tracker, which the caller sets afterwards.
+func seedContracts(t *tracker.Tracker, notifier *notify.Notifier, start int) error {
+	if start <= 1 {
+		return nil
+	}
+	t.SetFilter(processor.Filter{Types: map[models.TransactionType]bool{models.ContractCall: true}})
+
+	count := 0
+	err := t.StreamBlocks(address, 0, start-1, func(tx *models.Transaction) error {
+		notifier.Seen(tx)
+		count++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load earlier contract calls: %w", err)
+	}
+	slog.Info("Loaded earlier contract calls", "count", count, "end_block", start-1)
+	return nil
+}
+
+// printWatched prints one line for a watched transaction
+func printWatched(marker string, tx *models.Transaction) {
+	fmt.Printf("%s %s  block %s  %s  %s %s  %s\n", marker,
+		tx.DateTime.UTC().Format("2006-01-02 15:04:05"), tx.BlockNumber, tx.TransactionType,
+		tx.ValueFormatted, tx.AssetSymbol, tx.Hash)
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 30*time.Second, "Time between polls")
+	watchCmd.Flags().IntVar(&watchReorgDepth, "reorg-depth", watch.DefaultReorgDepth, "Number of recent blocks re-checked on every poll for reorganizations")
+	watchCmd.Flags().StringVarP(&watchOutput, "output", "o", "", "CSV, NDJSON or SQLite file to append confirmed transactions to")
+	watchCmd.Flags().StringVarP(&watchFormat, "format", "f", "", "Format of --output: csv, ndjson or sqlite (default: inferred from the file extension)")
+	watchCmd.Flags().StringVar(&watchCursor, "cursor", "", "File keeping the block to resume from after a restart")
+	watchCmd.Flags().StringVar(&watchMetrics, "metrics-listen", "", "Address to serve Prometheus metrics on, e.g. :9100 (disabled when empty)")
+	addFilterFlags(watchCmd)
+	rootCmd.AddCommand(watchCmd)
+}
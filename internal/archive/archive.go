@@ -0,0 +1,222 @@
+package archive
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one archived API response together with the request that produced it
+type Entry struct {
+	FetchedAt time.Time         `json:"fetchedAt"`
+	Endpoint  string            `json:"endpoint"`
+	Params    map[string]string `json:"params"`
+	Response  json.RawMessage   `json:"response"`
+}
+
+// rangeFile names the file recording the synced block range in the directory of an address
+const rangeFile = "range.json"
+
+// Range is the block range the responses of an address were fetched for. Replays request
+// the same range, since responses are keyed by the blocks asked for; an end of 99999999
+// means the latest block at the time.
+type Range struct {
+	StartBlock int       `json:"startBlock"`
+	EndBlock   int       `json:"endBlock"`
+	SyncedAt   time.Time `json:"syncedAt"`
+}
+
+// Contains reports whether r covers every block of other
+func (r Range) Contains(other Range) bool {
+	return r.StartBlock <= other.StartBlock && r.EndBlock >= other.EndBlock
+}
+
+// Archive stores raw Etherscan responses as gzip-compressed JSON files, one per stream,
+// block range and page, laid out as <dir>/<chain ID>/<address>/<action>_<start>-<end>_p<page>.json.gz
+type Archive struct {
+	dir string
+}
+
+// New creates an archive rooted at dir
+func New(dir string) *Archive {
+	return &Archive{
+		dir: dir,
+	}
+}
+
+// Dir returns the root directory of the archive
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Save writes a raw response and its request parameters, replacing any earlier response
+// to the same request. API keys must be removed from params by the caller.
+func (a *Archive) Save(endpoint string, params url.Values, body []byte, fetchedAt time.Time) error {
+	entry := Entry{
+		FetchedAt: fetchedAt.UTC(),
+		Endpoint:  endpoint,
+		Params:    make(map[string]string, len(params)),
+		Response:  json.RawMessage(body),
+	}
+	for key := range params {
+		entry.Params[key] = params.Get(key)
+	}
+
+	return writeFile(a.path(params), func(w io.Writer) error {
+		writer := gzip.NewWriter(w)
+		if err := json.NewEncoder(writer).Encode(entry); err != nil {
+			return err
+		}
+		return writer.Close()
+	})
+}
+
+// SaveRange records the block range synced for an address on a chain. A range already
+// recorded that covers it is kept, since its responses are still in the archive.
+func (a *Archive) SaveRange(chainID int, address string, r Range) error {
+	recorded, err := a.LoadRange(chainID, address)
+	if err != nil {
+		return err
+	}
+	if recorded != nil && recorded.Contains(r) {
+		return nil
+	}
+
+	r.SyncedAt = r.SyncedAt.UTC()
+	return writeFile(a.rangePath(chainID, address), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	})
+}
+
+// LoadRange returns the block range synced for an address on a chain, or nil for archives
+// written before ranges were recorded
+func (a *Archive) LoadRange(chainID int, address string) (*Range, error) {
+	filename := a.rangePath(chainID, address)
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive range: %w", err)
+	}
+
+	var r Range
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse archive range %s: %w", filename, err)
+	}
+	return &r, nil
+}
+
+// writeFile writes a file next to its target and renames it, so an interrupted run never
+// leaves a partial file
+func writeFile(filename string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if err := write(temp); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the archived raw response to a request
+func (a *Archive) Load(params url.Values) ([]byte, error) {
+	entry, err := a.Entry(params)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Response, nil
+}
+
+// Entry reads the archived entry for a request
+func (a *Archive) Entry(params url.Values) (*Entry, error) {
+	filename := a.path(params)
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no archived response for %s (%s)", params.Get("action"), filename)
+		}
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file %s: %w", filename, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file %s: %w", filename, err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse archive file %s: %w", filename, err)
+	}
+
+	return &entry, nil
+}
+
+// rangePath returns the file recording the synced block range of an address
+func (a *Archive) rangePath(chainID int, address string) string {
+	return filepath.Join(a.dir, strconv.Itoa(chainID), strings.ToLower(address), rangeFile)
+}
+
+// path returns the file a request is archived in. Transaction list streams are named by
+// action, block range, token contract if any and page; other requests by action and a
+// hash of their parameters.
+func (a *Archive) path(params url.Values) string {
+	chainID := params.Get("chainid")
+	if chainID == "" {
+		chainID = "1"
+	}
+
+	address := strings.ToLower(params.Get("address"))
+	if address == "" {
+		address = "_"
+	}
+
+	action := params.Get("action")
+	var name string
+	if params.Has("startblock") {
+		name = fmt.Sprintf("%s_%s-%s_p%s", action, params.Get("startblock"), params.Get("endblock"), params.Get("page"))
+		if contract := params.Get("contractaddress"); contract != "" {
+			name = fmt.Sprintf("%s_%s-%s_%s_p%s", action, params.Get("startblock"), params.Get("endblock"), strings.ToLower(contract), params.Get("page"))
+		}
+	} else {
+		sum := sha256.Sum256([]byte(params.Encode()))
+		name = fmt.Sprintf("%s_%s", action, hex.EncodeToString(sum[:8]))
+	}
+
+	return filepath.Join(a.dir, chainID, address, name+".json.gz")
+}
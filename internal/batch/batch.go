@@ -0,0 +1,257 @@
+package batch
+
+import (
+	"crypto-acc-tracking/internal/logging"
+	"crypto-acc-tracking/internal/output"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressStage names the progress of a batch over its addresses
+const progressStage = "batch addresses"
+
+// Entry is one address of a batch
+type Entry struct {
+	Address string
+	Label   string // Optional label used in account names
+	Chain   string // Optional chain, empty for the chain of the run
+}
+
+// Result is the outcome of exporting one entry
+type Result struct {
+	Entry
+	OutputFile   string
+	Transactions int
+	Assets       int
+	Duration     time.Duration
+	Err          error
+}
+
+// Job exports one entry and returns its export summary
+type Job func(entry Entry) (map[string]interface{}, error)
+
+// Load reads the addresses of a batch, either one per line or as a CSV file. A CSV file
+// may start with a header naming its address, label and chain columns; without one the
+// columns are taken in that order. Blank lines and lines starting with # are skipped, as
+// are repeated addresses.
+func Load(filename string) ([]Entry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"address": 0, "label": 1, "chain": 2}
+	seen := make(map[string]bool)
+	var entries []Entry
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse batch file: %w", err)
+		}
+
+		if line == 0 && isHeader(record) {
+			columns = make(map[string]int)
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			if _, ok := columns["address"]; !ok {
+				return nil, fmt.Errorf("batch file header has no address column")
+			}
+			continue
+		}
+
+		entry := Entry{
+			Address: field(record, columns, "address"),
+			Label:   field(record, columns, "label"),
+			Chain:   field(record, columns, "chain"),
+		}
+		if entry.Address == "" {
+			continue
+		}
+
+		key := strings.ToLower(entry.Address) + "@" + strings.ToLower(entry.Chain)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("batch file %s lists no addresses", filename)
+	}
+	return entries, nil
+}
+
+// isHeader reports whether a record names columns rather than holding an address
+func isHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "address") {
+			return true
+		}
+	}
+	return false
+}
+
+// field returns a trimmed column of a record, or "" when the record is too short
+func field(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// Run exports every entry with a pool of workers and returns the results in the order of
+// the entries. A failing entry, even one that panics, does not stop the others.
+func Run(entries []Entry, workers int, job Job) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(entries))
+	indexes := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		finished int
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := run(entries[i], job)
+				results[i] = result
+
+				mu.Lock()
+				finished++
+				if result.Err != nil {
+					slog.Error("Address failed", "address", result.Address, "finished", finished, "total", len(entries), "error", result.Err)
+				} else {
+					slog.Info("Address exported", "address", result.Address, "finished", finished, "total", len(entries), "transactions", result.Transactions, "duration", result.Duration.Round(time.Millisecond))
+				}
+				logging.Update(progressStage, finished, len(entries))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	logging.Finish(progressStage, finished)
+
+	return results
+}
+
+// run exports one entry, turning a panic into a failed result
+func run(entry Entry, job Job) (result Result) {
+	result.Entry = entry
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("panic: %v", r)
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	summary, err := job(entry)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.OutputFile, _ = summary["filename"].(string)
+	result.Transactions, _ = summary["total_transactions"].(int)
+	result.Assets, _ = summary["unique_assets"].(int)
+	return result
+}
+
+// Failed returns the number of failed results
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// WriteReport writes one CSV row per result with its status, row count and output file
+func WriteReport(filename string, results []Result) error {
+	file, err := output.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create batch report: %w", err)
+	}
+	defer file.Abort()
+
+	writer := csv.NewWriter(file)
+
+	header := []string{
+		"Address",
+		"Label",
+		"Chain",
+		"Status",
+		"Transactions",
+		"Unique Assets",
+		"Output File",
+		"Duration Seconds",
+		"Error",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write batch report header: %w", err)
+	}
+
+	for _, result := range results {
+		status, message := "success", ""
+		if result.Err != nil {
+			status, message = "failed", result.Err.Error()
+		}
+		record := []string{
+			result.Address,
+			result.Label,
+			result.Chain,
+			status,
+			strconv.Itoa(result.Transactions),
+			strconv.Itoa(result.Assets),
+			result.OutputFile,
+			strconv.FormatFloat(result.Duration.Seconds(), 'f', 3, 64),
+			message,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write batch report record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write batch report: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save batch report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package batch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	first  = "0x1111111111111111111111111111111111111111"
+	second = "0x2222222222222222222222222222222222222222"
+	third  = "0x3333333333333333333333333333333333333333"
+)
+
+// writeBatch writes a batch file and returns its name
+func writeBatch(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "batch.csv")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Entry
+	}{
+		{
+			name:    "one address per line",
+			content: first + "\n\n" + second + "\n",
+			want:    []Entry{{Address: first}, {Address: second}},
+		},
+		{
+			name:    "columns in default order",
+			content: first + ",Treasury,polygon\n" + second + ", Hot wallet\n",
+			want:    []Entry{{Address: first, Label: "Treasury", Chain: "polygon"}, {Address: second, Label: "Hot wallet"}},
+		},
+		{
+			name:    "header names the columns",
+			content: "Chain,Label,Address\narbitrum,Treasury," + first + "\n,," + second + "\n",
+			want:    []Entry{{Address: first, Label: "Treasury", Chain: "arbitrum"}, {Address: second}},
+		},
+		{
+			name:    "comment lines",
+			content: "# Wallets to export\n" + first + "\n# " + second + "\n",
+			want:    []Entry{{Address: first}},
+		},
+		{
+			name:    "repeated addresses",
+			content: first + ",One\n" + strings.ToUpper(first[:2]) + strings.ToUpper(first[2:]) + ",Two\n",
+			want:    []Entry{{Address: first, Label: "One"}},
+		},
+		{
+			name:    "same address on other chains",
+			content: first + ",,ethereum\n" + first + ",,base\n" + first + ",,ETHEREUM\n",
+			want:    []Entry{{Address: first, Chain: "ethereum"}, {Address: first, Chain: "base"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Load(writeBatch(t, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("got %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no addresses", "# nothing yet\n\n", "lists no addresses"},
+		{"only a header", "address,label\n", "lists no addresses"},
+		{"unbalanced quote", `"` + first + "\n", "failed to parse batch file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeBatch(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("missing file loaded")
+	}
+}
+
+func TestRunIsolatesFailures(t *testing.T) {
+	entries := []Entry{{Address: first}, {Address: second}, {Address: third}}
+	job := func(entry Entry) (map[string]interface{}, error) {
+		switch entry.Address {
+		case first:
+			panic("unexpected response")
+		case second:
+			return nil, errors.New("rate limited")
+		}
+		return map[string]interface{}{
+			"filename":           entry.Address + ".csv",
+			"total_transactions": 12,
+			"unique_assets":      3,
+		}, nil
+	}
+
+	for _, workers := range []int{0, 1, 3} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results := Run(entries, workers, job)
+
+			if len(results) != 3 || Failed(results) != 2 {
+				t.Fatalf("got %d results with %d failures, want 3 with 2", len(results), Failed(results))
+			}
+			for i, result := range results {
+				if result.Address != entries[i].Address {
+					t.Errorf("result %d is for %s, want %s", i, result.Address, entries[i].Address)
+				}
+			}
+			if err := results[0].Err; err == nil || !strings.Contains(err.Error(), "panic: unexpected response") {
+				t.Errorf("panicking entry returned %v", err)
+			}
+			if err := results[1].Err; err == nil || err.Error() != "rate limited" {
+				t.Errorf("failing entry returned %v", err)
+			}
+			if ok := results[2]; ok.Err != nil || ok.OutputFile != third+".csv" || ok.Transactions != 12 || ok.Assets != 3 {
+				t.Errorf("unexpected result %+v", ok)
+			}
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	results := []Result{
+		{
+			Entry:        Entry{Address: first, Label: "Treasury", Chain: "base"},
+			OutputFile:   "treasury.csv",
+			Transactions: 12,
+			Assets:       3,
+			Duration:     1500 * time.Millisecond,
+		},
+		{
+			Entry:    Entry{Address: second},
+			Duration: 250 * time.Millisecond,
+			Err:      errors.New("invalid API key"),
+		},
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.csv")
+	if err := WriteReport(filename, results); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"Address", "Label", "Chain", "Status", "Transactions", "Unique Assets", "Output File", "Duration Seconds", "Error"},
+		{first, "Treasury", "base", "success", "12", "3", "treasury.csv", "1.500", ""},
+		{second, "", "", "failed", "0", "0", "", "0.250", "invalid API key"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got\n%v\nwant\n%v", records, want)
+	}
+}
@@ -0,0 +1,370 @@
+package config
+
+import (
+	"bytes"
+	"crypto-acc-tracking/internal/notify"
+	"crypto-acc-tracking/internal/portfolio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix starts the name of every environment variable the tool reads
+	EnvPrefix = "CRYPTO_TRACKER_"
+
+	// DefaultKey is the apiKeys entry used for chains without a key of their own
+	DefaultKey = "default"
+
+	dirName  = "crypto-tracker"
+	fileName = "config.yaml"
+)
+
+// Config holds settings read from the configuration file and the environment. Empty
+// fields leave the built-in defaults in place.
+type Config struct {
+	APIKeys   map[string]string  `yaml:"apiKeys,omitempty"`   // Etherscan API keys by chain name, "default" for all other chains; a comma-separated list makes a key pool
+	Chain     string             `yaml:"chain,omitempty"`     // Chain of --address
+	Store     string             `yaml:"store,omitempty"`     // Local store of sync and export
+	RateLimit float64            `yaml:"rateLimit,omitempty"` // Etherscan requests per second, replacing the fixed delays
+	Portfolio string             `yaml:"portfolio,omitempty"` // Name of the wallets below, tracked when no wallet is given
+	Wallets   []portfolio.Wallet `yaml:"wallets,omitempty"`
+	Labels    map[string]string  `yaml:"labels,omitempty"` // Address labels used in account names
+	Output    Output             `yaml:"output,omitempty"`
+	Filters   Filters            `yaml:"filters,omitempty"`
+	Notify    notify.Config      `yaml:"notify,omitempty"` // Notification sinks and rules of watch
+	KeyPool   KeyPool            `yaml:"keyPool,omitempty"`
+}
+
+// KeyPool holds the settings of runs given several API keys for a chain
+type KeyPool struct {
+	RateLimit  float64 `yaml:"rateLimit,omitempty"`  // Requests per second of each key (default 5)
+	DailyLimit int     `yaml:"dailyLimit,omitempty"` // Requests per day of each key (default 100000, -1 for none)
+	UsageFile  string  `yaml:"usageFile,omitempty"`  // Daily request counts of the keys (default in the user cache directory)
+}
+
+// Output holds the default export settings
+type Output struct {
+	File          string `yaml:"file,omitempty"`
+	Format        string `yaml:"format,omitempty"`
+	Order         string `yaml:"order,omitempty"`
+	CSVSchema     string `yaml:"csvSchema,omitempty"`
+	SplitBy       string `yaml:"splitBy,omitempty"`
+	SplitTemplate string `yaml:"splitTemplate,omitempty"`
+	NoClobber     bool   `yaml:"noClobber,omitempty"`
+	Timestamp     bool   `yaml:"timestamp,omitempty"`
+	Rotate        int    `yaml:"rotate,omitempty"`
+}
+
+// Filters holds the default transaction filters
+type Filters struct {
+	FromDate     string   `yaml:"fromDate,omitempty"`
+	ToDate       string   `yaml:"toDate,omitempty"`
+	StartBlock   int      `yaml:"startBlock,omitempty"`
+	EndBlock     int      `yaml:"endBlock,omitempty"`
+	Types        []string `yaml:"types,omitempty"`
+	Token        string   `yaml:"token,omitempty"`
+	MinValue     string   `yaml:"minValue,omitempty"`
+	Status       string   `yaml:"status,omitempty"`
+	Counterparty string   `yaml:"counterparty,omitempty"`
+}
+
+// Find returns the configuration file to use: the explicit path if given, otherwise
+// $CRYPTO_TRACKER_CONFIG, otherwise crypto-tracker/config.yaml in $XDG_CONFIG_HOME
+// (default ~/.config) or one of $XDG_CONFIG_DIRS (default /etc/xdg). It returns an
+// empty path when no file exists.
+func Find(explicit string) (string, error) {
+	if explicit == "" {
+		explicit = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("failed to open config file: %w", err)
+		}
+		return explicit, nil
+	}
+
+	for _, dir := range searchDirs() {
+		candidate := filepath.Join(dir, dirName, fileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// searchDirs returns the XDG configuration directories in order of preference
+func searchDirs() []string {
+	var dirs []string
+
+	if home := os.Getenv("XDG_CONFIG_HOME"); home != "" {
+		dirs = append(dirs, home)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config"))
+	}
+
+	systemDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if systemDirs == "" {
+		systemDirs = "/etc/xdg"
+	}
+	for _, dir := range filepath.SplitList(systemDirs) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// Load reads a configuration file. An empty filename yields an empty configuration.
+func Load(filename string) (*Config, error) {
+	cfg := &Config{}
+	if filename == "" {
+		cfg.normalize()
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
+	cfg.normalize()
+	return cfg, nil
+}
+
+// normalize lowercases chain names and addresses so lookups are case-insensitive
+func (c *Config) normalize() {
+	keys := make(map[string]string, len(c.APIKeys))
+	for chain, key := range c.APIKeys {
+		keys[strings.ToLower(chain)] = key
+	}
+	c.APIKeys = keys
+
+	labels := make(map[string]string, len(c.Labels))
+	for address, label := range c.Labels {
+		labels[strings.ToLower(address)] = label
+	}
+	c.Labels = labels
+}
+
+// ApplyEnv overrides settings with CRYPTO_TRACKER_* variables from environ, given as
+// KEY=value pairs. CRYPTO_TRACKER_API_KEY sets the default key and
+// CRYPTO_TRACKER_API_KEY_<CHAIN> the key of one chain; either may list several keys,
+// separated by commas, to form a key pool.
+func (c *Config) ApplyEnv(environ []string) error {
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+
+		setting := strings.TrimPrefix(name, EnvPrefix)
+		if setting == "API_KEY" {
+			c.APIKeys[DefaultKey] = value
+			continue
+		}
+		if chain, ok := strings.CutPrefix(setting, "API_KEY_"); ok {
+			c.APIKeys[strings.ToLower(chain)] = value
+			continue
+		}
+
+		if err := c.setEnv(setting, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setEnv applies one environment setting; unknown names are ignored
+func (c *Config) setEnv(setting, value string) error {
+	var err error
+	switch setting {
+	case "CHAIN":
+		c.Chain = value
+	case "STORE":
+		c.Store = value
+	case "RATE_LIMIT":
+		c.RateLimit, err = strconv.ParseFloat(value, 64)
+	case "OUTPUT":
+		c.Output.File = value
+	case "FORMAT":
+		c.Output.Format = value
+	case "ORDER":
+		c.Output.Order = value
+	case "CSV_SCHEMA":
+		c.Output.CSVSchema = value
+	case "SPLIT_BY":
+		c.Output.SplitBy = value
+	case "SPLIT_TEMPLATE":
+		c.Output.SplitTemplate = value
+	case "NO_CLOBBER":
+		c.Output.NoClobber, err = strconv.ParseBool(value)
+	case "TIMESTAMP":
+		c.Output.Timestamp, err = strconv.ParseBool(value)
+	case "ROTATE":
+		c.Output.Rotate, err = strconv.Atoi(value)
+	case "FROM_DATE":
+		c.Filters.FromDate = value
+	case "TO_DATE":
+		c.Filters.ToDate = value
+	case "START_BLOCK":
+		c.Filters.StartBlock, err = strconv.Atoi(value)
+	case "END_BLOCK":
+		c.Filters.EndBlock, err = strconv.Atoi(value)
+	case "TYPES":
+		c.Filters.Types = strings.Split(value, ",")
+	case "TOKEN":
+		c.Filters.Token = value
+	case "MIN_VALUE":
+		c.Filters.MinValue = value
+	case "STATUS":
+		c.Filters.Status = value
+	case "COUNTERPARTY":
+		c.Filters.Counterparty = value
+	case "KEY_RATE_LIMIT":
+		c.KeyPool.RateLimit, err = strconv.ParseFloat(value, 64)
+	case "KEY_DAILY_LIMIT":
+		c.KeyPool.DailyLimit, err = strconv.Atoi(value)
+	case "KEY_USAGE_FILE":
+		c.KeyPool.UsageFile = value
+	}
+	return err
+}
+
+// APIKey returns the API key of a chain, falling back to the default key
+func (c *Config) APIKey(chain string) string {
+	if key := c.APIKeys[strings.ToLower(chain)]; key != "" {
+		return key
+	}
+	return c.APIKeys[DefaultKey]
+}
+
+// Flags returns the settings that map onto command line flags, keyed by flag name,
+// as the strings the flags would be given
+func (c *Config) Flags() map[string]string {
+	flags := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	setInt := func(name string, value int) {
+		if value != 0 {
+			flags[name] = strconv.Itoa(value)
+		}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			flags[name] = "true"
+		}
+	}
+
+	set("chain", c.Chain)
+	set("store", c.Store)
+	if c.RateLimit != 0 {
+		flags["rate-limit"] = strconv.FormatFloat(c.RateLimit, 'f', -1, 64)
+	}
+
+	set("output", c.Output.File)
+	set("format", c.Output.Format)
+	set("order", c.Output.Order)
+	set("csv-schema", c.Output.CSVSchema)
+	set("split-by", c.Output.SplitBy)
+	set("split-template", c.Output.SplitTemplate)
+	setBool("no-clobber", c.Output.NoClobber)
+	setBool("timestamp", c.Output.Timestamp)
+	setInt("rotate", c.Output.Rotate)
+
+	set("from-date", c.Filters.FromDate)
+	set("to-date", c.Filters.ToDate)
+	setInt("start-block", c.Filters.StartBlock)
+	setInt("end-block", c.Filters.EndBlock)
+	set("types", strings.Join(c.Filters.Types, ","))
+	set("token", c.Filters.Token)
+	set("min-value", c.Filters.MinValue)
+	set("status", c.Filters.Status)
+	set("counterparty", c.Filters.Counterparty)
+
+	return flags
+}
+
+// Masked returns a copy of the configuration with API keys masked for display
+func (c *Config) Masked() *Config {
+	masked := *c
+	masked.APIKeys = make(map[string]string, len(c.APIKeys))
+	for chain, key := range c.APIKeys {
+		masked.APIKeys[chain] = maskList(key)
+	}
+
+	masked.Notify.Sinks = make([]notify.SinkConfig, len(c.Notify.Sinks))
+	for i, sink := range c.Notify.Sinks {
+		sink.URL = maskURL(sink.URL)
+		sink.Secret = Mask(sink.Secret)
+		if len(sink.Headers) > 0 {
+			headers := make(map[string]string, len(sink.Headers))
+			for name, value := range sink.Headers {
+				headers[name] = Mask(value)
+			}
+			sink.Headers = headers
+		}
+		masked.Notify.Sinks[i] = sink
+	}
+	return &masked
+}
+
+// maskURL hides everything after the host of a URL, where webhook URLs keep their tokens
+func maskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Mask(raw)
+	}
+	if u.Path == "" && u.RawQuery == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host + "/" + strings.Repeat("*", 8)
+}
+
+// maskList masks every secret of a comma-separated list, e.g. the keys of a key pool
+func maskList(list string) string {
+	secrets := strings.Split(list, ",")
+	for i, secret := range secrets {
+		secrets[i] = Mask(strings.TrimSpace(secret))
+	}
+	return strings.Join(secrets, ",")
+}
+
+// Mask hides all but the last four characters of a secret, or all of a short one
+func Mask(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", len(secret)-4) + secret[len(secret)-4:]
+}
+
+// YAML returns the configuration as a YAML document
+func (c *Config) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,87 @@
+package etherscan
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+)
+
+// GetBlockNumberByTime returns the last block mined at or before the given Unix timestamp
+func (c *Client) GetBlockNumberByTime(timestamp int64) (int, error) {
+	params := url.Values{
+		"module":    []string{"block"},
+		"action":    []string{"getblocknobytime"},
+		"timestamp": []string{strconv.FormatInt(timestamp, 10)},
+		"closest":   []string{"before"},
+	}
+
+	result, err := c.getStringResult(params)
+	if err != nil {
+		return 0, err
+	}
+
+	block, err := strconv.Atoi(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse block number: %s", result)
+	}
+
+	return block, nil
+}
+
+// GetBalanceAtBlock returns the native balance in wei of an address at a block.
+// The endpoint is only available to Etherscan API Pro keys.
+func (c *Client) GetBalanceAtBlock(address string, block int) (*big.Int, error) {
+	params := url.Values{
+		"module":  []string{"account"},
+		"action":  []string{"balancehistory"},
+		"address": []string{address},
+		"blockno": []string{strconv.Itoa(block)},
+	}
+
+	return c.getBigIntResult(params)
+}
+
+// GetTokenBalanceAtBlock returns the raw ERC-20 balance of an address at a block.
+// The endpoint is only available to Etherscan API Pro keys.
+func (c *Client) GetTokenBalanceAtBlock(contractAddress, address string, block int) (*big.Int, error) {
+	params := url.Values{
+		"module":          []string{"account"},
+		"action":          []string{"tokenbalancehistory"},
+		"contractaddress": []string{contractAddress},
+		"address":         []string{address},
+		"blockno":         []string{strconv.Itoa(block)},
+	}
+
+	return c.getBigIntResult(params)
+}
+
+// getBigIntResult performs a request whose result is a base 10 integer string
+func (c *Client) getBigIntResult(params url.Values) (*big.Int, error) {
+	result, err := c.getStringResult(params)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := new(big.Int).SetString(result, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse balance: %s", result)
+	}
+
+	return value, nil
+}
+
+// getStringResult performs a request whose result is a single string value
+func (c *Client) getStringResult(params url.Values) (string, error) {
+	if c.apiKey != "" {
+		params.Set("apikey", c.apiKey)
+	}
+
+	response, err := c.makeRequest(params)
+	if err != nil {
+		return "", err
+	}
+
+	result, _ := response.Result.(string)
+	return result, nil
+}
@@ -1,8 +1,10 @@
 package etherscan
 
 import (
+	"crypto-acc-tracking/internal/metrics"
 	"crypto-acc-tracking/internal/models"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,16 +15,28 @@ import (
 
 const (
 	BaseURL        = "https://api.etherscan.io/api"
+	V2BaseURL      = "https://api.etherscan.io/v2/api"
 	DefaultTimeout = 30 * time.Second
 	MaxRetries     = 3
 	RetryDelay     = 5 * time.Second
 )
 
+// Archive records raw API responses and serves them back for offline reprocessing
+type Archive interface {
+	Save(endpoint string, params url.Values, body []byte, fetchedAt time.Time) error
+	Load(params url.Values) ([]byte, error)
+}
+
 // Client represents the Etherscan API client
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	baseURL    string
+	chainID    int
+	archive    Archive
+	replay     bool
+	limiter    *RateLimiter
+	keys       *KeyPool
 }
 
 // New creates a new Etherscan client
@@ -36,7 +50,56 @@ func New(apiKey string) *Client {
 	}
 }
 
-// GetNormalTransactions fetches normal transactions for an address
+// NewForChain creates a new Etherscan client for the given chain using the multichain API
+func NewForChain(apiKey string, chain models.Chain) *Client {
+	client := New(apiKey)
+	if chain.ID != models.DefaultChain.ID {
+		client.baseURL = V2BaseURL
+		client.chainID = chain.ID
+	}
+	return client
+}
+
+// SetBaseURL sends requests to another Etherscan-compatible endpoint, such as a proxy
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// ArchiveTo saves every raw response to the archive as it is received
+func (c *Client) ArchiveTo(archive Archive) {
+	c.archive = archive
+	c.replay = false
+}
+
+// ReplayFrom serves every request from the archive without network calls
+func (c *Client) ReplayFrom(archive Archive) {
+	c.archive = archive
+	c.replay = true
+}
+
+// SetRateLimiter makes every request wait for the limiter
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.limiter = limiter
+}
+
+// SetKeyPool sends every request with a key of the pool instead of the client's own key,
+// switching to another key when one is rate limited or rejected
+func (c *Client) SetKeyPool(pool *KeyPool) {
+	c.keys = pool
+}
+
+// Throttled reports whether requests are paced by a rate limiter or key pool
+func (c *Client) Throttled() bool {
+	return c.limiter != nil || c.keys != nil
+}
+
+// Offline reports whether responses are replayed from an archive
+func (c *Client) Offline() bool {
+	return c.replay
+}
+
+// GetNormalTransactions fetches normal transactions for an address, or ErrNoResults when
+// there are none in the block range
 func (c *Client) GetNormalTransactions(address string, startBlock, endBlock int, page, offset int) ([]models.EtherscanNormalTx, error) {
 	params := url.Values{
 		"module":     []string{"account"},
@@ -53,33 +116,21 @@ func (c *Client) GetNormalTransactions(address string, startBlock, endBlock int,
 		params.Set("apikey", c.apiKey)
 	}
 
-	var response models.EtherscanResponse
-	if err := c.makeRequest(params, &response); err != nil {
-		return nil, err
-	}
-
-	if response.Status != "1" {
-		if response.Message == "NOTOK" {
-			return nil, fmt.Errorf("Etherscan API error: Rate limit exceeded or invalid request. Consider using an API key with -k flag")
-		}
-		return nil, fmt.Errorf("Etherscan API error: %s", response.Message)
-	}
-
-	// Convert interface{} to []models.EtherscanNormalTx
-	resultBytes, err := json.Marshal(response.Result)
+	response, err := c.makeRequest(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal result: %w", err)
+		return nil, err
 	}
 
 	var transactions []models.EtherscanNormalTx
-	if err := json.Unmarshal(resultBytes, &transactions); err != nil {
+	if err := decodeResult(response, &transactions); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal transactions: %w", err)
 	}
 
 	return transactions, nil
 }
 
-// GetInternalTransactions fetches internal transactions for an address
+// GetInternalTransactions fetches internal transactions for an address, or ErrNoResults
+// when there are none in the block range
 func (c *Client) GetInternalTransactions(address string, startBlock, endBlock int, page, offset int) ([]models.EtherscanInternalTx, error) {
 	params := url.Values{
 		"module":     []string{"account"},
@@ -96,37 +147,22 @@ func (c *Client) GetInternalTransactions(address string, startBlock, endBlock in
 		params.Set("apikey", c.apiKey)
 	}
 
-	var response models.EtherscanResponse
-	if err := c.makeRequest(params, &response); err != nil {
-		return nil, err
-	}
-
-	if response.Status != "1" {
-		if response.Message == "NOTOK" {
-			return nil, fmt.Errorf("Etherscan API error: Rate limit exceeded or invalid request. Consider using an API key with -k flag")
-		}
-		if response.Message == "No transactions found" {
-			return []models.EtherscanInternalTx{}, nil // Return empty slice for no transactions
-		}
-		return nil, fmt.Errorf("Etherscan API error: %s", response.Message)
-	}
-
-	// Convert interface{} to []models.EtherscanInternalTx
-	resultBytes, err := json.Marshal(response.Result)
+	response, err := c.makeRequest(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal result: %w", err)
+		return nil, err
 	}
 
 	var transactions []models.EtherscanInternalTx
-	if err := json.Unmarshal(resultBytes, &transactions); err != nil {
+	if err := decodeResult(response, &transactions); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal internal transactions: %w", err)
 	}
 
 	return transactions, nil
 }
 
-// GetTokenTransactions fetches ERC-20 token transactions for an address
-func (c *Client) GetTokenTransactions(address string, startBlock, endBlock int, page, offset int) ([]models.EtherscanTokenTx, error) {
+// GetTokenTransactions fetches ERC-20 token transactions for an address, limited to one token
+// contract unless contractAddress is empty, or ErrNoResults when there are none
+func (c *Client) GetTokenTransactions(address, contractAddress string, startBlock, endBlock int, page, offset int) ([]models.EtherscanTokenTx, error) {
 	params := url.Values{
 		"module":     []string{"account"},
 		"action":     []string{"tokentx"},
@@ -138,41 +174,29 @@ func (c *Client) GetTokenTransactions(address string, startBlock, endBlock int,
 		"sort":       []string{"desc"},
 	}
 
+	if contractAddress != "" {
+		params.Set("contractaddress", contractAddress)
+	}
 	if c.apiKey != "" {
 		params.Set("apikey", c.apiKey)
 	}
 
-	var response models.EtherscanResponse
-	if err := c.makeRequest(params, &response); err != nil {
-		return nil, err
-	}
-
-	if response.Status != "1" {
-		if response.Message == "NOTOK" {
-			return nil, fmt.Errorf("Etherscan API error: Rate limit exceeded or invalid request. Consider using an API key with -k flag")
-		}
-		if response.Message == "No transactions found" {
-			return []models.EtherscanTokenTx{}, nil // Return empty slice for no transactions
-		}
-		return nil, fmt.Errorf("Etherscan API error: %s", response.Message)
-	}
-
-	// Convert interface{} to []models.EtherscanTokenTx
-	resultBytes, err := json.Marshal(response.Result)
+	response, err := c.makeRequest(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal result: %w", err)
+		return nil, err
 	}
 
 	var transactions []models.EtherscanTokenTx
-	if err := json.Unmarshal(resultBytes, &transactions); err != nil {
+	if err := decodeResult(response, &transactions); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal token transactions: %w", err)
 	}
 
 	return transactions, nil
 }
 
-// GetNFTTransactions fetches ERC-721 NFT transactions for an address
-func (c *Client) GetNFTTransactions(address string, startBlock, endBlock int, page, offset int) ([]models.EtherscanNFTTx, error) {
+// GetNFTTransactions fetches ERC-721 NFT transactions for an address, limited to one token
+// contract unless contractAddress is empty, or ErrNoResults when there are none
+func (c *Client) GetNFTTransactions(address, contractAddress string, startBlock, endBlock int, page, offset int) ([]models.EtherscanNFTTx, error) {
 	params := url.Values{
 		"module":     []string{"account"},
 		"action":     []string{"tokennfttx"},
@@ -184,75 +208,203 @@ func (c *Client) GetNFTTransactions(address string, startBlock, endBlock int, pa
 		"sort":       []string{"desc"},
 	}
 
+	if contractAddress != "" {
+		params.Set("contractaddress", contractAddress)
+	}
 	if c.apiKey != "" {
 		params.Set("apikey", c.apiKey)
 	}
 
-	var response models.EtherscanResponse
-	if err := c.makeRequest(params, &response); err != nil {
+	response, err := c.makeRequest(params)
+	if err != nil {
 		return nil, err
 	}
 
-	if response.Status != "1" {
-		if response.Message == "NOTOK" {
-			return nil, fmt.Errorf("Etherscan API error: Rate limit exceeded or invalid request. Consider using an API key with -k flag")
-		}
-		if response.Message == "No transactions found" {
-			return []models.EtherscanNFTTx{}, nil // Return empty slice for no transactions
-		}
-		return nil, fmt.Errorf("Etherscan API error: %s", response.Message)
+	var transactions []models.EtherscanNFTTx
+	if err := decodeResult(response, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal NFT transactions: %w", err)
 	}
 
-	// Convert interface{} to []models.EtherscanNFTTx
-	resultBytes, err := json.Marshal(response.Result)
+	return transactions, nil
+}
+
+// GetERC1155Transactions fetches ERC-1155 multi-token transactions for an address, limited
+// to one token contract unless contractAddress is empty, or ErrNoResults when there are none
+func (c *Client) GetERC1155Transactions(address, contractAddress string, startBlock, endBlock int, page, offset int) ([]models.EtherscanERC1155Tx, error) {
+	params := url.Values{
+		"module":     []string{"account"},
+		"action":     []string{"token1155tx"},
+		"address":    []string{address},
+		"startblock": []string{strconv.Itoa(startBlock)},
+		"endblock":   []string{strconv.Itoa(endBlock)},
+		"page":       []string{strconv.Itoa(page)},
+		"offset":     []string{strconv.Itoa(offset)},
+		"sort":       []string{"desc"},
+	}
+
+	if contractAddress != "" {
+		params.Set("contractaddress", contractAddress)
+	}
+	if c.apiKey != "" {
+		params.Set("apikey", c.apiKey)
+	}
+
+	response, err := c.makeRequest(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal result: %w", err)
+		return nil, err
 	}
 
-	var transactions []models.EtherscanNFTTx
-	if err := json.Unmarshal(resultBytes, &transactions); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal NFT transactions: %w", err)
+	var transactions []models.EtherscanERC1155Tx
+	if err := decodeResult(response, &transactions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ERC-1155 transactions: %w", err)
 	}
 
 	return transactions, nil
 }
 
-// makeRequest performs HTTP request to Etherscan API with retry logic
-func (c *Client) makeRequest(params url.Values, response interface{}) error {
-	requestURL := fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
+// makeRequest performs HTTP request to Etherscan API with retry logic and returns the
+// response once the API reports success. Network failures, server errors and unreadable
+// bodies are retried; API errors are returned at once as one of the typed errors, leaving
+// backing off from ErrRateLimited to the caller.
+func (c *Client) makeRequest(params url.Values) (*models.EtherscanResponse, error) {
+	if c.chainID != 0 {
+		params.Set("chainid", strconv.Itoa(c.chainID))
+	}
+
+	// Archived requests are keyed without the API key so it never lands on disk
+	var archiveParams url.Values
+	if c.archive != nil {
+		archiveParams = make(url.Values, len(params))
+		for key, values := range params {
+			if key != "apikey" {
+				archiveParams[key] = values
+			}
+		}
+	}
 
+	if c.replay {
+		body, err := c.archive.Load(archiveParams)
+		if err != nil {
+			return nil, err
+		}
+		var response models.EtherscanResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal archived response: %w", err)
+		}
+		return &response, checkResponse(&response, body)
+	}
+
+	action := params.Get("action")
 	var lastErr error
 	for attempt := 0; attempt < MaxRetries; attempt++ {
 		if attempt > 0 {
+			metrics.EtherscanRetries.WithLabelValues(action).Inc()
 			time.Sleep(RetryDelay)
 		}
 
-		resp, err := c.httpClient.Get(requestURL)
-		if err != nil {
-			lastErr = fmt.Errorf("HTTP request failed: %w", err)
+		response, retry, err := c.attempt(params, archiveParams)
+		if retry {
+			lastErr = err
 			continue
 		}
+		return response, err
+	}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+	return nil, fmt.Errorf("request failed after %d attempts: %w", MaxRetries, lastErr)
+}
+
+// attempt sends a request once, or with a key pool once per key until one is neither rate
+// limited nor rejected. It reports whether a failure may be retried.
+func (c *Client) attempt(params, archiveParams url.Values) (*models.EtherscanResponse, bool, error) {
+	if c.keys == nil {
+		return c.send(params, archiveParams)
+	}
 
+	for tried := 1; ; tried++ {
+		waitStart := time.Now()
+		key, err := c.keys.acquire()
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response body: %w", err)
-			continue
+			return nil, false, err
 		}
+		metrics.RateLimiterWait.Observe(time.Since(waitStart).Seconds())
+		params.Set("apikey", key.key)
 
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		response, retry, err := c.send(params, archiveParams)
+		c.keys.release(key, err)
+		if tried < c.keys.Size() && (errors.Is(err, ErrRateLimited) || errors.Is(err, ErrInvalidAPIKey)) {
 			continue
 		}
+		return response, retry, err
+	}
+}
 
-		if err := json.Unmarshal(body, response); err != nil {
-			lastErr = fmt.Errorf("failed to unmarshal response: %w", err)
-			continue
+// send performs one HTTP request, classifying the response, and reports whether a
+// failure may be retried
+func (c *Client) send(params, archiveParams url.Values) (*models.EtherscanResponse, bool, error) {
+	action := params.Get("action")
+	if c.limiter != nil {
+		waitStart := time.Now()
+		c.limiter.Wait()
+		metrics.RateLimiterWait.Observe(time.Since(waitStart).Seconds())
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s?%s", c.baseURL, params.Encode()))
+	if err != nil {
+		observeRequest(action, "error", start)
+		return nil, true, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	observeRequest(action, strconv.Itoa(resp.StatusCode), start)
+
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		metrics.EtherscanRateLimited.WithLabelValues(action).Inc()
+		return nil, false, fmt.Errorf("API returned status %d: %w", resp.StatusCode, ErrRateLimited)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, newUpstreamError(resp.StatusCode, "", body)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, newUpstreamError(resp.StatusCode, "", body)
+	}
+
+	var response models.EtherscanResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, true, newUpstreamError(resp.StatusCode, fmt.Sprintf("failed to unmarshal response: %v", err), body)
+	}
+
+	err = checkResponse(&response, body)
+	if errors.Is(err, ErrRateLimited) {
+		metrics.EtherscanRateLimited.WithLabelValues(action).Inc()
+	}
+
+	// Rate limit and key errors say nothing about the data, so a replay must not see them
+	if c.archive != nil && !errors.Is(err, ErrRateLimited) && !errors.Is(err, ErrInvalidAPIKey) {
+		if err := c.archive.Save(c.baseURL, archiveParams, body, time.Now()); err != nil {
+			return nil, false, fmt.Errorf("failed to archive response: %w", err)
 		}
+	}
+
+	return &response, false, err
+}
 
-		return nil
+// decodeResult decodes the result of a successful response into target
+func decodeResult(response *models.EtherscanResponse, target interface{}) error {
+	// Convert interface{} to the typed result
+	resultBytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
 	}
+	return json.Unmarshal(resultBytes, target)
+}
 
-	return fmt.Errorf("request failed after %d attempts: %w", MaxRetries, lastErr)
+// observeRequest records a finished Etherscan request with its status and latency
+func observeRequest(action, status string, start time.Time) {
+	metrics.EtherscanRequests.WithLabelValues(action, status).Inc()
+	metrics.EtherscanRequestDuration.WithLabelValues(action).Observe(time.Since(start).Seconds())
 }
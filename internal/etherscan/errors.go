@@ -0,0 +1,84 @@
+package etherscan
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxErrorBody is the number of bytes of a response body kept in an UpstreamError
+const maxErrorBody = 512
+
+// Errors of the Etherscan API, wrapped with the details of the failing request so callers
+// can tell them apart with errors.Is
+var (
+	// ErrRateLimited means the API rejected a request for exceeding the rate limit or the
+	// daily quota of the key; the request may succeed after backing off
+	ErrRateLimited = errors.New("rate limit exceeded")
+	// ErrInvalidAPIKey means the API key is missing, malformed or revoked; retrying cannot help
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrNoResults means the request was valid but matched nothing, e.g. an empty wallet
+	ErrNoResults = errors.New("no results found")
+	// ErrResultWindowTooLarge means page × offset exceeded the 10,000 results the API serves
+	// per query; the block range must be narrowed instead of paging further
+	ErrResultWindowTooLarge = errors.New("result window is too large")
+	// ErrUpstream is matched by every UpstreamError
+	ErrUpstream = errors.New("upstream error")
+)
+
+// UpstreamError is a failure reported by Etherscan that none of the other errors describe:
+// an HTTP error status, an unreadable body or an unrecognized API error message
+type UpstreamError struct {
+	StatusCode int    // HTTP status of the response
+	Message    string // Message and result of the API error, when the body could be parsed
+	Body       string // Start of the response body
+}
+
+// Error describes the failure with its status and message or body
+func (e *UpstreamError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Etherscan API error (status %d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Etherscan API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Is makes every UpstreamError match ErrUpstream
+func (e *UpstreamError) Is(target error) bool {
+	return target == ErrUpstream
+}
+
+// newUpstreamError creates an UpstreamError, truncating the body
+func newUpstreamError(statusCode int, message string, body []byte) *UpstreamError {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	return &UpstreamError{StatusCode: statusCode, Message: message, Body: string(body)}
+}
+
+// checkResponse converts an unsuccessful API response into one of the typed errors.
+// Etherscan reports errors with status 200 and "status": "0", naming the problem in the
+// message or, for NOTOK, in the result, e.g. "Max rate limit reached".
+func checkResponse(response *models.EtherscanResponse, body []byte) error {
+	if response.Status == "1" {
+		return nil
+	}
+
+	message := response.Message
+	if result, ok := response.Result.(string); ok && result != "" {
+		message += ": " + result
+	}
+
+	text := strings.ToLower(message)
+	switch {
+	case strings.Contains(text, "rate limit"):
+		return fmt.Errorf("Etherscan API error: %s: %w", message, ErrRateLimited)
+	case strings.Contains(text, "invalid api key") || strings.Contains(text, "missing/invalid api key"):
+		return fmt.Errorf("Etherscan API error: %s: %w", message, ErrInvalidAPIKey)
+	case strings.Contains(text, "no transactions found") || strings.Contains(text, "no records found"):
+		return fmt.Errorf("Etherscan API error: %s: %w", message, ErrNoResults)
+	case strings.Contains(text, "result window is too large"):
+		return fmt.Errorf("Etherscan API error: %s: %w", message, ErrResultWindowTooLarge)
+	}
+	return newUpstreamError(200, message, body)
+}
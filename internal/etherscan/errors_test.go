@@ -0,0 +1,75 @@
+package etherscan
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		file string
+		want error // nil for success
+	}{
+		{"ok.json", nil},
+		{"rate_limit.json", ErrRateLimited},
+		{"daily_limit.json", ErrRateLimited},
+		{"invalid_key.json", ErrInvalidAPIKey},
+		{"missing_key.json", ErrInvalidAPIKey},
+		{"no_transactions.json", ErrNoResults},
+		{"no_records.json", ErrNoResults},
+		{"window_too_large.json", ErrResultWindowTooLarge},
+		{"invalid_address.json", ErrUpstream},
+		{"query_timeout.json", ErrUpstream},
+	}
+	typed := []error{ErrRateLimited, ErrInvalidAPIKey, ErrNoResults, ErrResultWindowTooLarge, ErrUpstream}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSuffix(tt.file, ".json"), func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var response models.EtherscanResponse
+			if err := json.Unmarshal(body, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkResponse(&response, body)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("got %v, want success", err)
+				}
+				return
+			}
+			for _, target := range typed {
+				if got := errors.Is(err, target); got != (target == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, target, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUpstreamErrorKeepsMessage(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "invalid_address.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response models.EtherscanResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	var upstream *UpstreamError
+	if !errors.As(checkResponse(&response, body), &upstream) {
+		t.Fatal("not an UpstreamError")
+	}
+	if upstream.StatusCode != 200 || upstream.Message != "NOTOK: Error! Invalid address format" {
+		t.Errorf("got %+v", upstream)
+	}
+}
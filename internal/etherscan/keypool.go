@@ -0,0 +1,198 @@
+package etherscan
+
+import (
+	"crypto-acc-tracking/internal/metrics"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultKeyRateLimit = 5.0              // Requests per second of one key, the free tier limit
+	DefaultDailyLimit   = 100000           // Requests per day of one key, the free tier quota
+	RateLimitBench      = time.Minute      // How long a rate-limited key is left out of the pool
+	InvalidKeyBench     = 30 * time.Minute // How long a rejected key is left out of the pool
+)
+
+// usageWarnings are the shares of the daily quota of a pool, in percent, at which a warning
+// is logged once per day
+var usageWarnings = []int{80, 90, 100}
+
+// KeyPool spreads requests over several API keys, each paced by its own rate limiter.
+// Keys that are rate limited or rejected are benched for a while, and keys that used up
+// their daily quota are skipped until the next UTC day. One pool can be shared by several
+// clients, e.g. all workers of a batch run.
+type KeyPool struct {
+	mu         sync.Mutex
+	keys       []*poolKey
+	next       int // Index of the key tried first, rotating so ties are spread evenly
+	usage      *Usage
+	dailyLimit int
+	warnedDay  string
+	warned     int // Highest usage warning logged on warnedDay, in percent
+}
+
+// poolKey is one key of a pool
+type poolKey struct {
+	key          string
+	limiter      *RateLimiter
+	benchedUntil time.Time
+	invalid      bool // Benched because the API rejected the key
+}
+
+// ParseKeys splits a comma-separated list of API keys, dropping blanks and duplicates
+func ParseKeys(list string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(list, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// NewKeyPool creates a pool of keys allowing perSecond requests per second each,
+// DefaultKeyRateLimit when 0, and DefaultDailyLimit requests per day
+func NewKeyPool(keys []string, perSecond float64) *KeyPool {
+	if perSecond <= 0 {
+		perSecond = DefaultKeyRateLimit
+	}
+	p := &KeyPool{dailyLimit: DefaultDailyLimit}
+	for _, key := range keys {
+		p.keys = append(p.keys, &poolKey{key: key, limiter: NewRateLimiter(perSecond)})
+	}
+	return p
+}
+
+// SetDailyLimit sets the requests per day of each key; 0 removes the limit
+func (p *KeyPool) SetDailyLimit(limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dailyLimit = limit
+}
+
+// SetUsage counts the requests of every key in usage, which enables the daily limit
+func (p *KeyPool) SetUsage(usage *Usage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usage = usage
+}
+
+// Size returns the number of keys in the pool
+func (p *KeyPool) Size() int {
+	return len(p.keys)
+}
+
+// acquire picks the usable key that can send soonest, waits for its rate limiter and
+// counts the request. When every key is benched for rate limits it waits for the first
+// to return; when none can be used again today it fails with ErrRateLimited, or with
+// ErrInvalidAPIKey when the API rejected all of them.
+func (p *KeyPool) acquire() (*poolKey, error) {
+	for {
+		p.mu.Lock()
+		now := clock()
+		var best *poolKey
+		var wake time.Time
+		exhausted := false
+		for i := range p.keys {
+			k := p.keys[(p.next+i)%len(p.keys)]
+			switch {
+			case now.Before(k.benchedUntil):
+				if !k.invalid && (wake.IsZero() || k.benchedUntil.Before(wake)) {
+					wake = k.benchedUntil
+				}
+			case p.exhausted(k):
+				exhausted = true
+			case best == nil || k.limiter.ready().Before(best.limiter.ready()):
+				best = k
+			}
+		}
+
+		if best != nil {
+			p.next = (p.next + 1) % len(p.keys)
+			p.count(best)
+			wait := best.limiter.reserve()
+			p.mu.Unlock()
+			time.Sleep(wait)
+			return best, nil
+		}
+		limit := p.dailyLimit
+		p.mu.Unlock()
+
+		switch {
+		case !wake.IsZero():
+			time.Sleep(wake.Sub(clock()))
+		case exhausted:
+			return nil, fmt.Errorf("every key of the pool used up its daily quota of %d requests: %w", limit, ErrRateLimited)
+		default:
+			return nil, fmt.Errorf("every key of the pool was rejected: %w", ErrInvalidAPIKey)
+		}
+	}
+}
+
+// release benches a key whose request failed with a rate limit or key error
+func (p *KeyPool) release(k *poolKey, err error) {
+	var bench time.Duration
+	var reason string
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		bench, reason = RateLimitBench, "rate_limited"
+	case errors.Is(err, ErrInvalidAPIKey):
+		bench, reason = InvalidKeyBench, "invalid"
+	default:
+		return
+	}
+
+	p.mu.Lock()
+	k.benchedUntil = clock().Add(bench)
+	k.invalid = reason == "invalid"
+	p.mu.Unlock()
+
+	metrics.EtherscanKeysBenched.WithLabelValues(reason).Inc()
+	slog.Warn("Benching API key", "key", KeyID(k.key), "reason", reason, "for", bench, "error", err)
+}
+
+// exhausted reports whether a key used up its daily quota; the caller holds the lock
+func (p *KeyPool) exhausted(k *poolKey) bool {
+	return p.usage != nil && p.dailyLimit > 0 && p.usage.Count(k.key) >= p.dailyLimit
+}
+
+// count records a request of a key and warns as the pool approaches its daily quota; the
+// caller holds the lock
+func (p *KeyPool) count(k *poolKey) {
+	if p.usage == nil {
+		return
+	}
+	p.usage.add(k.key)
+	if p.dailyLimit <= 0 {
+		return
+	}
+
+	used := 0
+	for _, other := range p.keys {
+		used += p.usage.Count(other.key)
+	}
+	quota := p.dailyLimit * len(p.keys)
+	percent := used * 100 / quota
+
+	if day := today(); day != p.warnedDay {
+		p.warnedDay = day
+		p.warned = 0
+	}
+	reached := 0
+	for _, threshold := range usageWarnings {
+		if percent >= threshold {
+			reached = threshold
+		}
+	}
+	if reached > p.warned {
+		p.warned = reached
+		slog.Warn("API key pool is approaching its daily quota", "used", used, "quota", quota, "percent", percent, "keys", len(p.keys))
+	}
+}
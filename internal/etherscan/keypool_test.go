@@ -0,0 +1,192 @@
+package etherscan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setClock makes quotas and benches run on a fake clock for the test
+func setClock(t *testing.T, at time.Time) *time.Time {
+	now := at
+	previous := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = previous })
+	return &now
+}
+
+func acquireKey(t *testing.T, p *KeyPool) string {
+	t.Helper()
+	k, err := p.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.key
+}
+
+func TestKeyPoolRotates(t *testing.T) {
+	p := NewKeyPool([]string{"a", "b", "c"}, 1000)
+
+	var got []string
+	for i := 0; i < 6; i++ {
+		got = append(got, acquireKey(t, p))
+	}
+	if fmt.Sprint(got) != "[a b c a b c]" {
+		t.Errorf("keys used %v, want each in turn", got)
+	}
+}
+
+func TestKeyPoolBenchesRateLimitedKey(t *testing.T) {
+	now := setClock(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	p := NewKeyPool([]string{"a", "b"}, 1000)
+
+	k, err := p.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.release(k, fmt.Errorf("Max rate limit reached: %w", ErrRateLimited))
+	if !k.benchedUntil.Equal(now.Add(RateLimitBench)) || k.invalid {
+		t.Fatalf("benched until %v (invalid %v), want %v", k.benchedUntil, k.invalid, now.Add(RateLimitBench))
+	}
+
+	for i := 0; i < 3; i++ {
+		if key := acquireKey(t, p); key != "b" {
+			t.Fatalf("acquired benched key %s", key)
+		}
+	}
+
+	// Back after the bench
+	*now = now.Add(RateLimitBench + time.Second)
+	used := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		used[acquireKey(t, p)] = true
+	}
+	if !used["a"] {
+		t.Error("rate-limited key was not restored after its bench")
+	}
+}
+
+func TestKeyPoolBenchesInvalidKey(t *testing.T) {
+	now := setClock(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	p := NewKeyPool([]string{"a", "b"}, 1000)
+
+	a, _ := p.acquire()
+	p.release(a, fmt.Errorf("Invalid API Key: %w", ErrInvalidAPIKey))
+	if !a.benchedUntil.Equal(now.Add(InvalidKeyBench)) || !a.invalid {
+		t.Fatalf("benched until %v (invalid %v), want %v", a.benchedUntil, a.invalid, now.Add(InvalidKeyBench))
+	}
+
+	// Still benched after a rate limit bench would have ended
+	*now = now.Add(RateLimitBench + time.Second)
+	b, err := p.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.key != "b" {
+		t.Fatalf("acquired rejected key %s", b.key)
+	}
+
+	// Once every key is rejected the pool gives up instead of waiting
+	p.release(b, fmt.Errorf("Invalid API Key: %w", ErrInvalidAPIKey))
+	if _, err := p.acquire(); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("acquire returned %v, want ErrInvalidAPIKey", err)
+	}
+
+	*now = now.Add(InvalidKeyBench)
+	if key := acquireKey(t, p); key != "a" && key != "b" {
+		t.Errorf("acquired %s", key)
+	}
+}
+
+func TestKeyPoolErrorsDoNotBench(t *testing.T) {
+	p := NewKeyPool([]string{"a"}, 1000)
+	k, _ := p.acquire()
+	p.release(k, &UpstreamError{StatusCode: 502})
+	p.release(k, fmt.Errorf("no data: %w", ErrNoResults))
+	if !k.benchedUntil.IsZero() {
+		t.Errorf("key benched for an error unrelated to it")
+	}
+}
+
+func TestClientSwitchesToNextKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("apikey")
+		keys = append(keys, key)
+		if key == "limited" {
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1","hash":"0xabc"}]}`)
+	}))
+	defer server.Close()
+
+	client := New("")
+	client.SetBaseURL(server.URL)
+	client.SetKeyPool(NewKeyPool([]string{"limited", "good"}, 1000))
+
+	txs, err := client.GetNormalTransactions("0x1111111111111111111111111111111111111111", 0, 100, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Hash != "0xabc" {
+		t.Errorf("got %v, want the result of the second key", txs)
+	}
+	if fmt.Sprint(keys) != "[limited good]" {
+		t.Errorf("keys sent %v, want the limited key then the good one", keys)
+	}
+}
+
+func TestUsageSavesPreviousDayOnRollover(t *testing.T) {
+	now := setClock(t, time.Date(2024, 5, 1, 23, 59, 0, 0, time.UTC))
+	filename := filepath.Join(t.TempDir(), "usage.json")
+
+	usage, err := OpenUsage(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		usage.add("key")
+	}
+
+	*now = now.Add(2 * time.Minute)
+	if count := usage.Count("key"); count != 0 {
+		t.Errorf("count on the new day is %d, want 0", count)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved usageFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Date != "2024-05-01" || saved.Requests[KeyID("key")] != 3 {
+		t.Errorf("saved %+v, want the 3 requests of 2024-05-01", saved)
+	}
+}
+
+func TestPoolDailyLimit(t *testing.T) {
+	setClock(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	usage, err := OpenUsage(filepath.Join(t.TempDir(), "usage.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewKeyPool([]string{"a", "b"}, 1000)
+	p.SetUsage(usage)
+	p.SetDailyLimit(2)
+
+	for i := 0; i < 4; i++ {
+		acquireKey(t, p)
+	}
+	if _, err := p.acquire(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("acquire past the daily quota returned %v, want ErrRateLimited", err)
+	}
+}
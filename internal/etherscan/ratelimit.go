@@ -0,0 +1,47 @@
+package etherscan
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces requests out evenly so they stay under a number of requests per
+// second. One limiter can be shared by several clients, e.g. all wallets of a run.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perSecond requests per second
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	return &RateLimiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+	}
+}
+
+// Wait blocks until the next request may be sent
+func (l *RateLimiter) Wait() {
+	time.Sleep(l.reserve())
+}
+
+// reserve claims the next slot and returns how long to wait for it
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+// ready returns when the next request may be sent without waiting
+func (l *RateLimiter) ready() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.next
+}
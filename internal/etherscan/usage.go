@@ -0,0 +1,191 @@
+package etherscan
+
+import (
+	"crypto-acc-tracking/internal/metrics"
+	"crypto-acc-tracking/internal/output"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// usageSaveInterval is the number of requests counted between saves of the usage file
+const usageSaveInterval = 100
+
+// Usage counts the requests sent with each API key on the current UTC day, the period of
+// Etherscan's daily quotas, and persists the counts so runs sharing a file add up. Keys are
+// stored as fingerprints, never in the clear.
+type Usage struct {
+	mu       sync.Mutex
+	filename string
+	date     string
+	counts   map[string]int // Requests of the day by key fingerprint, saved or not
+	unsaved  map[string]int // Requests not yet added to the file
+	pending  int
+}
+
+// usageFile is the JSON document of a usage file
+type usageFile struct {
+	Date     string         `json:"date"`
+	Requests map[string]int `json:"requests"` // By key fingerprint
+}
+
+// OpenUsage loads the usage counts of today from filename; a missing file or one of an
+// earlier day starts from zero
+func OpenUsage(filename string) (*Usage, error) {
+	u := &Usage{
+		filename: filename,
+		date:     today(),
+		counts:   make(map[string]int),
+		unsaved:  make(map[string]int),
+	}
+
+	saved, err := u.load()
+	if err != nil {
+		return nil, err
+	}
+	for id, count := range saved {
+		u.counts[id] = count
+		metrics.EtherscanKeyRequests.WithLabelValues(id).Set(float64(count))
+	}
+	return u, nil
+}
+
+// Count returns the number of requests sent with a key today
+func (u *Usage) Count(key string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.rollover()
+	return u.counts[KeyID(key)]
+}
+
+// Save adds the requests counted since the last save to the file. The file is read again
+// first, so counts saved by other runs in the meantime are kept.
+func (u *Usage) Save() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.save()
+}
+
+// add counts one request sent with a key and returns the requests of the key today
+func (u *Usage) add(key string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.rollover()
+
+	id := KeyID(key)
+	u.counts[id]++
+	u.unsaved[id]++
+	metrics.EtherscanKeyRequests.WithLabelValues(id).Set(float64(u.counts[id]))
+
+	u.pending++
+	if u.pending >= usageSaveInterval {
+		if err := u.save(); err != nil {
+			// Counting goes on in memory; the next save tries again
+			slog.Warn("Failed to save API key usage", "error", err)
+		}
+	}
+	return u.counts[id]
+}
+
+// rollover starts from zero when the UTC day has changed, saving the counts of the day
+// before first so they are not lost
+func (u *Usage) rollover() {
+	if day := today(); day != u.date {
+		if len(u.unsaved) > 0 {
+			if err := u.save(); err != nil {
+				slog.Warn("Failed to save API key usage", "date", u.date, "error", err)
+			}
+		}
+		u.date = day
+		u.counts = make(map[string]int)
+		u.unsaved = make(map[string]int)
+		u.pending = 0
+		metrics.EtherscanKeyRequests.Reset()
+	}
+}
+
+// save merges the unsaved counts into the file; the caller holds the lock
+func (u *Usage) save() error {
+	saved, err := u.load()
+	if err != nil {
+		return err
+	}
+	for id, count := range u.unsaved {
+		saved[id] += count
+	}
+
+	if err := os.MkdirAll(filepath.Dir(u.filename), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	data, err := json.MarshalIndent(usageFile{Date: u.date, Requests: saved}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode key usage: %w", err)
+	}
+
+	file, err := output.Create(u.filename)
+	if err != nil {
+		return fmt.Errorf("failed to save key usage: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Abort()
+		return fmt.Errorf("failed to save key usage: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save key usage: %w", err)
+	}
+
+	u.counts = saved
+	for id, count := range saved {
+		metrics.EtherscanKeyRequests.WithLabelValues(id).Set(float64(count))
+	}
+	u.unsaved = make(map[string]int)
+	u.pending = 0
+	return nil
+}
+
+// load reads the counts of the current day from the file
+func (u *Usage) load() (map[string]int, error) {
+	counts := make(map[string]int)
+
+	data, err := os.ReadFile(u.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return counts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key usage: %w", err)
+	}
+
+	var file usageFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key usage %s: %w", u.filename, err)
+	}
+	if file.Date != u.date {
+		return counts, nil
+	}
+	for id, count := range file.Requests {
+		counts[id] = count
+	}
+	return counts, nil
+}
+
+// clock returns the current time of quotas and benches
+var clock = time.Now
+
+// today returns the current UTC date, the day Etherscan quotas are counted in
+func today() string {
+	return clock().UTC().Format("2006-01-02")
+}
+
+// KeyID returns a fingerprint of an API key that identifies it in usage files and
+// metrics without revealing it
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// Appendable reports whether outputs of a format can grow run by run
+func Appendable(format Format) bool {
+	switch format {
+	case FormatCSV, FormatNDJSON, FormatSQLite:
+		return true
+	}
+	return false
+}
+
+// Append adds transactions to an output file, creating it if it does not exist. CSV and
+// NDJSON rows are appended to the end of the file, SQLite rows are upserted.
+func Append(filename string, transactions []*models.Transaction, options Options) error {
+	if options.Format == "" {
+		options.Format = InferFormat(filename)
+	}
+
+	start := time.Now()
+	err := appendFormat(filename, transactions, options)
+	observeExport(options.Format, len(transactions), start, err)
+	return err
+}
+
+// appendFormat appends transactions in the format of the options
+func appendFormat(filename string, transactions []*models.Transaction, options Options) error {
+	switch options.Format {
+	case FormatCSV:
+		schema := options.CSV
+		if schema == nil {
+			schema = DefaultCSVSchema()
+			schema.Validate()
+		}
+		return appendCSV(filename, transactions, schema)
+	case FormatNDJSON:
+		return appendNDJSON(filename, transactions)
+	case FormatSQLite:
+		return NewSQLiteExporter(filename, options).Export(transactions)
+	default:
+		return fmt.Errorf("cannot append to %s output, use csv, ndjson or sqlite", options.Format)
+	}
+}
+
+// openAppend opens a file for appending and reports whether it was empty
+func openAppend(filename string) (*os.File, bool, error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, false, err
+	}
+	return file, info.Size() == 0, nil
+}
+
+// appendCSV appends rows to a CSV file, writing the header first if the file is new
+func appendCSV(filename string, transactions []*models.Transaction, schema *CSVSchema) error {
+	file, empty, err := openAppend(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Comma, _ = utf8.DecodeRuneInString(schema.Delimiter)
+	if empty {
+		if err := writer.Write(schema.header()); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+	for _, tx := range transactions {
+		if err := writer.Write(schema.record(tx)); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to save CSV file: %w", err)
+	}
+	return file.Close()
+}
+
+// appendNDJSON appends one line of JSON per transaction to a file
+func appendNDJSON(filename string, transactions []*models.Transaction) error {
+	file, _, err := openAppend(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open NDJSON file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, tx := range transactions {
+		if err := encoder.Encode(tx); err != nil {
+			return fmt.Errorf("failed to write NDJSON record: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write NDJSON file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to save NDJSON file: %w", err)
+	}
+	return file.Close()
+}
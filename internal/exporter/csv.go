@@ -2,82 +2,118 @@ package exporter
 
 import (
 	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
 	"encoding/csv"
 	"fmt"
-	"os"
 	"strings"
+	"unicode/utf8"
 )
 
 // CSVExporter handles exporting transactions to CSV format
 type CSVExporter struct {
+	fileNaming
 	filename string
+	schema   *CSVSchema
+	file     *output.File
+	writer   *csv.Writer
+	counter  *SummaryCounter
 }
 
-// NewCSVExporter creates a new CSV exporter
+// NewCSVExporter creates a new CSV exporter with the default schema
 func NewCSVExporter(filename string) *CSVExporter {
+	schema := DefaultCSVSchema()
+	schema.Validate()
+	return NewCSVExporterWithSchema(filename, schema)
+}
+
+// NewCSVExporterWithSchema creates a new CSV exporter with a validated custom schema
+func NewCSVExporterWithSchema(filename string, schema *CSVSchema) *CSVExporter {
 	return &CSVExporter{
 		filename: filename,
+		schema:   schema,
 	}
 }
 
 // Export writes transactions to a CSV file
 func (e *CSVExporter) Export(transactions []*models.Transaction) error {
-	file, err := os.Create(e.filename)
+	if err := e.Open(); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		if err := e.Write(tx); err != nil {
+			e.Abort()
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// Open creates the CSV file and writes the header
+func (e *CSVExporter) Open() error {
+	file, err := e.create(e.filename)
 	if err != nil {
 		return fmt.Errorf("failed to create CSV file: %w", err)
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	e.file = file
+	e.writer = csv.NewWriter(file)
+	e.writer.Comma, _ = utf8.DecodeRuneInString(e.schema.Delimiter)
+	e.counter = NewSummaryCounter()
 
 	// Write CSV header
-	header := []string{
-		"Transaction Hash",
-		"Date & Time",
-		"From Address",
-		"To Address",
-		"Transaction Type",
-		"Asset Contract Address",
-		"Asset Symbol / Name",
-		"Token ID",
-		"Value / Amount",
-		"Gas Fee (ETH)",
-		"Block Number",
-		"Status",
-	}
-
-	if err := writer.Write(header); err != nil {
+	if err := e.writer.Write(e.schema.header()); err != nil {
+		e.Abort()
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
-	// Write transaction data
-	for _, tx := range transactions {
-		record := []string{
-			tx.Hash,
-			tx.DateTime.Format("2006-01-02 15:04:05 UTC"),
-			tx.FromAddress,
-			tx.ToAddress,
-			string(tx.TransactionType),
-			tx.AssetContractAddr,
-			e.formatAssetInfo(tx.AssetSymbol, tx.AssetName),
-			tx.TokenID,
-			e.formatValue(tx.ValueFormatted, tx.AssetSymbol),
-			tx.GasFeeETH,
-			tx.BlockNumber,
-			tx.Status,
-		}
+	return nil
+}
 
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write CSV record: %w", err)
-		}
+// Write appends one transaction to the CSV file
+func (e *CSVExporter) Write(tx *models.Transaction) error {
+	if err := e.writer.Write(e.schema.record(tx)); err != nil {
+		return fmt.Errorf("failed to write CSV record: %w", err)
+	}
+	e.counter.Add(tx)
+	return nil
+}
+
+// Close flushes the CSV file and moves it into place
+func (e *CSVExporter) Close() error {
+	if e.file == nil {
+		return nil
+	}
+	defer func() { e.file = nil }()
+
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	if err := e.file.Commit(); err != nil {
+		return fmt.Errorf("failed to save CSV file: %w", err)
 	}
 
 	return nil
 }
 
+// Abort discards the partially written CSV file
+func (e *CSVExporter) Abort() {
+	if e.file != nil {
+		e.file.Abort()
+		e.file = nil
+	}
+}
+
+// Summary returns a summary of the transactions written since Open
+func (e *CSVExporter) Summary() map[string]interface{} {
+	return e.counter.Summary(e.filename)
+}
+
 // formatAssetInfo combines symbol and name for better readability
-func (e *CSVExporter) formatAssetInfo(symbol, name string) string {
+func formatAssetInfo(symbol, name string) string {
 	if symbol == "" && name == "" {
 		return ""
 	}
@@ -94,45 +130,88 @@ func (e *CSVExporter) formatAssetInfo(symbol, name string) string {
 }
 
 // formatValue formats the value with symbol for better readability
-func (e *CSVExporter) formatValue(value, symbol string) string {
+func formatValue(value, symbol string) string {
+	value = trimAmount(value)
+	if value == "0" || symbol == "" {
+		return value
+	}
+
+	return fmt.Sprintf("%s %s", value, symbol)
+}
+
+// trimAmount removes trailing zeros from a decimal amount for cleaner display
+func trimAmount(value string) string {
 	if value == "" || value == "0" {
 		return "0"
 	}
 
-	// Remove trailing zeros for cleaner display
-	value = strings.TrimRight(value, "0")
-	value = strings.TrimRight(value, ".")
-
-	if symbol == "" {
-		return value
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(value, "0")
+		value = strings.TrimRight(value, ".")
 	}
 
-	return fmt.Sprintf("%s %s", value, symbol)
+	return value
 }
 
 // GetExportSummary returns a summary of the export operation
 func (e *CSVExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
-	summary := map[string]interface{}{
-		"total_transactions": len(transactions),
-		"filename":           e.filename,
-	}
+	return Summarize(transactions, e.filename)
+}
 
-	// Count transactions by type
-	typeCounts := make(map[models.TransactionType]int)
+// Summarize returns a summary of the transactions written to a file
+func Summarize(transactions []*models.Transaction, filename string) map[string]interface{} {
+	counter := NewSummaryCounter()
 	for _, tx := range transactions {
-		typeCounts[tx.TransactionType]++
+		counter.Add(tx)
 	}
+	return counter.Summary(filename)
+}
 
-	summary["transaction_types"] = typeCounts
+// SummaryCounter accumulates export summary counts one transaction at a time
+type SummaryCounter struct {
+	total      int
+	types      map[models.TransactionType]int
+	directions map[models.Direction]int
+	assets     map[string]bool
+}
+
+// NewSummaryCounter creates an empty summary counter
+func NewSummaryCounter() *SummaryCounter {
+	return &SummaryCounter{
+		types:      make(map[models.TransactionType]int),
+		directions: make(map[models.Direction]int),
+		assets:     make(map[string]bool),
+	}
+}
+
+// Add counts a transaction
+func (c *SummaryCounter) Add(tx *models.Transaction) {
+	c.total++
+
+	// Count transactions by type
+	c.types[tx.TransactionType]++
+
+	// Count transactions by direction, internal moves net out for the wallet set
+	c.directions[tx.Direction]++
 
 	// Count unique assets
-	assets := make(map[string]bool)
-	for _, tx := range transactions {
-		if tx.AssetSymbol != "" {
-			assets[tx.AssetSymbol] = true
-		}
+	if tx.AssetSymbol != "" {
+		c.assets[tx.AssetSymbol] = true
+	}
+}
+
+// Summary returns the counts in the export summary format
+func (c *SummaryCounter) Summary(filename string) map[string]interface{} {
+	if c == nil {
+		c = NewSummaryCounter()
 	}
-	summary["unique_assets"] = len(assets)
 
-	return summary
+	return map[string]interface{}{
+		"total_transactions": c.total,
+		"filename":           filename,
+		"transaction_types":  c.types,
+		"directions":         c.directions,
+		"internal_moves":     c.directions[models.DirectionInternalMove],
+		"unique_assets":      len(c.assets),
+	}
 }
@@ -0,0 +1,257 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultCSVDateFormat is the Go time layout used for the Date & Time column
+const DefaultCSVDateFormat = "2006-01-02 15:04:05 MST"
+
+// CSVColumn selects a transaction field and the header it is written under
+type CSVColumn struct {
+	Field  string `json:"field"`
+	Header string `json:"header"`
+}
+
+// CSVSchema configures the columns and formatting of a CSV export.
+//
+// Field names are the JSON names of models.Transaction fields (hash, dateTime,
+// assetDecimals, gasFeeWei, ...) plus the derived fields "asset" (symbol and name),
+// "amount" (quantity without symbol) and "amountWithSymbol".
+type CSVSchema struct {
+	Columns          []CSVColumn `json:"columns"`
+	Delimiter        string      `json:"delimiter"`
+	DecimalSeparator string      `json:"decimalSeparator"`
+	DateFormat       string      `json:"dateFormat"` // Go layout, "rfc3339" or "unix"
+	TimeZone         string      `json:"timeZone"`   // IANA name such as "Europe/Berlin"
+
+	location *time.Location
+}
+
+// transactionFieldIndex maps JSON field names of models.Transaction to their struct index
+var transactionFieldIndex = transactionFields()
+
+// decimalFields are formatted with the configured decimal separator
+var decimalFields = map[string]bool{
+	"amount":           true,
+	"amountWithSymbol": true,
+	"valueFormatted":   true,
+	"gasFeeEth":        true,
+}
+
+// DefaultCSVSchema returns the schema of the standard CSV export
+func DefaultCSVSchema() *CSVSchema {
+	return &CSVSchema{
+		Columns: []CSVColumn{
+			{Field: "hash", Header: "Transaction Hash"},
+			{Field: "dateTime", Header: "Date & Time"},
+			{Field: "fromAddress", Header: "From Address"},
+			{Field: "toAddress", Header: "To Address"},
+			{Field: "transactionType", Header: "Transaction Type"},
+			{Field: "assetContractAddr", Header: "Asset Contract Address"},
+			{Field: "asset", Header: "Asset Symbol / Name"},
+			{Field: "tokenId", Header: "Token ID"},
+			{Field: "amountWithSymbol", Header: "Value / Amount"},
+			{Field: "gasFeeEth", Header: "Gas Fee (ETH)"},
+			{Field: "blockNumber", Header: "Block Number"},
+			{Field: "status", Header: "Status"},
+			{Field: "direction", Header: "Direction"},
+		},
+		Delimiter:        ",",
+		DecimalSeparator: ".",
+		DateFormat:       DefaultCSVDateFormat,
+		TimeZone:         "UTC",
+	}
+}
+
+// LoadCSVSchema reads a CSV schema from a JSON file; unset settings keep their defaults
+func LoadCSVSchema(filename string) (*CSVSchema, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV schema file: %w", err)
+	}
+
+	// Columns are cleared first so decoding does not merge into the default entries
+	schema := DefaultCSVSchema()
+	defaultColumns := schema.Columns
+	schema.Columns = nil
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("failed to parse CSV schema file: %w", err)
+	}
+	if schema.Columns == nil {
+		schema.Columns = defaultColumns
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// Validate checks the schema and resolves its time zone
+func (s *CSVSchema) Validate() error {
+	if len(s.Columns) == 0 {
+		return fmt.Errorf("CSV schema has no columns")
+	}
+
+	for i, column := range s.Columns {
+		if !CSVFieldExists(column.Field) {
+			return fmt.Errorf("unknown CSV field: %s (available: %s)", column.Field, strings.Join(CSVFields(), ", "))
+		}
+		if column.Header == "" {
+			s.Columns[i].Header = column.Field
+		}
+	}
+
+	if s.Delimiter == "" {
+		s.Delimiter = ","
+	}
+	delimiter, size := utf8.DecodeRuneInString(s.Delimiter)
+	if size != len(s.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError {
+		return fmt.Errorf("invalid CSV delimiter: %q", s.Delimiter)
+	}
+
+	if s.DecimalSeparator == "" {
+		s.DecimalSeparator = "."
+	}
+	if s.DateFormat == "" {
+		s.DateFormat = DefaultCSVDateFormat
+	}
+
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid CSV time zone %q: %w", s.TimeZone, err)
+	}
+	s.location = location
+
+	return nil
+}
+
+// header returns the column headers of the schema
+func (s *CSVSchema) header() []string {
+	header := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		header[i] = column.Header
+	}
+	return header
+}
+
+// record returns the formatted column values of a transaction
+func (s *CSVSchema) record(tx *models.Transaction) []string {
+	record := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		value := s.fieldValue(tx, column.Field)
+		if decimalFields[column.Field] && s.DecimalSeparator != "." {
+			value = strings.Replace(value, ".", s.DecimalSeparator, 1)
+		}
+		record[i] = value
+	}
+	return record
+}
+
+// fieldValue returns the string value of a derived or transaction field
+func (s *CSVSchema) fieldValue(tx *models.Transaction, field string) string {
+	switch field {
+	case "asset":
+		return formatAssetInfo(tx.AssetSymbol, tx.AssetName)
+	case "amount":
+		return trimAmount(tx.ValueFormatted)
+	case "amountWithSymbol":
+		return formatValue(tx.ValueFormatted, tx.AssetSymbol)
+	case "dateTime":
+		return s.formatTime(tx.DateTime)
+	}
+
+	index, ok := transactionFieldIndex[field]
+	if !ok {
+		return ""
+	}
+
+	value := reflect.ValueOf(tx).Elem().FieldByIndex(index)
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return s.formatTime(v)
+	case *big.Int:
+		return bigString(v)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return value.String()
+	}
+}
+
+// formatTime formats a timestamp in the schema time zone and date format
+func (s *CSVSchema) formatTime(t time.Time) string {
+	location := s.location
+	if location == nil {
+		location = time.UTC
+	}
+
+	switch strings.ToLower(s.DateFormat) {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "rfc3339":
+		return t.In(location).Format(time.RFC3339)
+	default:
+		return t.In(location).Format(s.DateFormat)
+	}
+}
+
+// CSVFields returns every field name usable in a CSV schema
+func CSVFields() []string {
+	fields := []string{"asset", "amount", "amountWithSymbol"}
+	t := reflect.TypeOf(models.Transaction{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonFieldName(t.Field(i)); name != "" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// CSVFieldExists reports whether a field name is usable in a CSV schema
+func CSVFieldExists(field string) bool {
+	for _, name := range CSVFields() {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// transactionFields maps JSON field names of models.Transaction to their struct index
+func transactionFields() map[string][]int {
+	fields := make(map[string][]int)
+	t := reflect.TypeOf(models.Transaction{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonFieldName(t.Field(i)); name != "" {
+			fields[name] = t.Field(i).Index
+		}
+	}
+	return fields
+}
+
+// jsonFieldName returns the JSON name of a struct field
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,157 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readCSV exports the sample transactions with a schema and reads the file back
+func readCSV(t *testing.T, schema *CSVSchema) [][]string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "wallet.csv")
+	var exp *CSVExporter
+	if schema == nil {
+		exp = NewCSVExporter(filename)
+	} else {
+		exp = NewCSVExporterWithSchema(filename, schema)
+	}
+	if err := exp.Export(sampleTransactions()); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	if schema != nil {
+		reader.Comma = []rune(schema.Delimiter)[0]
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestCSVDefaultSchema(t *testing.T) {
+	records := readCSV(t, nil)
+
+	want := [][]string{
+		{"Transaction Hash", "Date & Time", "From Address", "To Address", "Transaction Type", "Asset Contract Address", "Asset Symbol / Name", "Token ID", "Value / Amount", "Gas Fee (ETH)", "Block Number", "Status", "Direction"},
+		{"0xaaa", "2023-12-31 22:30:00 UTC", testOther, testOwner, "ETH Transfer", "", "ETH (Ethereum)", "", "1.5 ETH", "0.000021", "18000000", "Success", "In"},
+		{"0xbbb", "2024-01-02 09:15:00 UTC", testOwner, testOther, "ERC-20 Transfer", testToken, "USDC (USD Coin)", "", "2.5 USDC", "", "18000100", "Success", "Out"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got\n%v\nwant\n%v", records, want)
+	}
+}
+
+func TestCSVCustomSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   [][]string
+	}{
+		{
+			name: "renamed and reordered columns",
+			schema: `{"columns": [
+				{"field": "blockNumber", "header": "Block"},
+				{"field": "hash", "header": "Tx"},
+				{"field": "amount"},
+				{"field": "value", "header": "Raw"},
+				{"field": "assetDecimals", "header": "Decimals"}
+			]}`,
+			want: [][]string{
+				{"Block", "Tx", "amount", "Raw", "Decimals"},
+				{"18000000", "0xaaa", "1.5", "1500000000000000000", "18"},
+				{"18000100", "0xbbb", "2.5", "2500000", "6"},
+			},
+		},
+		{
+			name: "semicolons and decimal commas",
+			schema: `{"columns": [{"field": "hash"}, {"field": "amountWithSymbol"}, {"field": "gasFeeEth"}],
+				"delimiter": ";", "decimalSeparator": ","}`,
+			want: [][]string{
+				{"hash", "amountWithSymbol", "gasFeeEth"},
+				{"0xaaa", "1,5 ETH", "0,000021"},
+				{"0xbbb", "2,5 USDC", ""},
+			},
+		},
+		{
+			name:   "time zone and date format",
+			schema: `{"columns": [{"field": "dateTime", "header": "Datum"}], "dateFormat": "02.01.2006 15:04 MST", "timeZone": "Europe/Berlin"}`,
+			want: [][]string{
+				{"Datum"},
+				{"31.12.2023 23:30 CET"},
+				{"02.01.2024 10:15 CET"},
+			},
+		},
+		{
+			name:   "unix dates",
+			schema: `{"columns": [{"field": "dateTime", "header": "Unix"}], "dateFormat": "unix"}`,
+			want: [][]string{
+				{"Unix"},
+				{"1704061800"},
+				{"1704186900"},
+			},
+		},
+		{
+			name:   "RFC 3339 in a time zone",
+			schema: `{"columns": [{"field": "dateTime"}], "dateFormat": "rfc3339", "timeZone": "America/New_York", "delimiter": "\t"}`,
+			want: [][]string{
+				{"dateTime"},
+				{"2023-12-31T17:30:00-05:00"},
+				{"2024-01-02T04:15:00-05:00"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := loadSchema(t, tt.schema)
+			if records := readCSV(t, schema); !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("got\n%v\nwant\n%v", records, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVSchemaErrors(t *testing.T) {
+	tests := map[string]string{
+		`{"columns": []}`:                              "no columns",
+		`{"columns": [{"field": "nonsense"}]}`:         "unknown CSV field: nonsense",
+		`{"delimiter": "\""}`:                          "invalid CSV delimiter",
+		`{"delimiter": ";;"}`:                          "invalid CSV delimiter",
+		`{"timeZone": "Mars/Olympus_Mons"}`:            "invalid CSV time zone",
+		`{"columns": [{"field": "hash"}], "oops": [1}`: "failed to parse CSV schema file",
+	}
+	for content, want := range tests {
+		filename := filepath.Join(t.TempDir(), "schema.json")
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadCSVSchema(filename); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want %q", content, err, want)
+		}
+	}
+}
+
+// loadSchema loads a CSV schema from JSON like the --csv-schema flag
+func loadSchema(t *testing.T, content string) *CSVSchema {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := LoadCSVSchema(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format identifies an output file format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+	FormatSQLite  Format = "sqlite"
+	FormatXLSX    Format = "xlsx"
+	Beancount     Format = "beancount"
+	Ledger        Format = "ledger"
+	HLedger       Format = "hledger"
+)
+
+// Exporter writes processed transactions to an output file
+type Exporter interface {
+	Export(transactions []*models.Transaction) error
+	GetExportSummary(transactions []*models.Transaction) map[string]interface{}
+}
+
+// StreamExporter is an Exporter that can also write transactions one at a time as
+// they become available, so memory use does not grow with the number of transactions.
+// Close completes the output; Abort discards it and leaves any previous output in place.
+type StreamExporter interface {
+	Exporter
+	Open() error
+	Write(tx *models.Transaction) error
+	Close() error
+	Abort()
+	Summary() map[string]interface{}
+}
+
+// Options configures how transactions are exported
+type Options struct {
+	Format   Format               `json:"format"`
+	Owned    map[string]bool      `json:"owned,omitempty"`
+	Labels   map[string]string    `json:"labels,omitempty"`
+	Accounts AccountTemplates     `json:"accounts"`
+	CSV      *CSVSchema           `json:"csv,omitempty"`
+	Order    processor.SortOrder  `json:"order"`
+	Filter   processor.FilterSpec `json:"filter"`
+	Naming   output.Naming        `json:"-"`
+
+	SplitBy       Partition `json:"splitBy,omitempty"`
+	SplitTemplate string    `json:"splitTemplate,omitempty"`
+}
+
+// extensions maps file extensions to the format they imply
+var extensions = map[string]Format{
+	".csv":       FormatCSV,
+	".json":      FormatJSON,
+	".ndjson":    FormatNDJSON,
+	".jsonl":     FormatNDJSON,
+	".parquet":   FormatParquet,
+	".sqlite":    FormatSQLite,
+	".sqlite3":   FormatSQLite,
+	".db":        FormatSQLite,
+	".xlsx":      FormatXLSX,
+	".beancount": Beancount,
+	".bean":      Beancount,
+	".ledger":    Ledger,
+	".journal":   HLedger,
+	".hledger":   HLedger,
+}
+
+// Extension returns the usual file extension of a format
+func Extension(format Format) string {
+	switch format {
+	case HLedger:
+		return ".journal"
+	case "":
+		return ".csv"
+	}
+	return "." + string(format)
+}
+
+// New creates an exporter for the configured format, inferring it from the
+// file extension when no format is set
+func New(filename string, options Options) (Exporter, error) {
+	if options.Format == "" {
+		options.Format = InferFormat(filename)
+	}
+
+	exp, err := newExporter(filename, options)
+	if err != nil {
+		return nil, err
+	}
+	return &meteredExporter{Exporter: exp, format: options.Format}, nil
+}
+
+// newExporter creates the exporter of a format without metering it
+func newExporter(filename string, options Options) (Exporter, error) {
+	var exp Exporter
+	switch options.Format {
+	case FormatCSV:
+		if options.CSV != nil {
+			exp = NewCSVExporterWithSchema(filename, options.CSV)
+		} else {
+			exp = NewCSVExporter(filename)
+		}
+	case FormatJSON:
+		exp = NewJSONExporter(filename)
+	case FormatNDJSON:
+		exp = NewNDJSONExporter(filename)
+	case FormatParquet:
+		exp = NewParquetExporter(filename)
+	case FormatSQLite:
+		exp = NewSQLiteExporter(filename, options)
+	case FormatXLSX:
+		exp = NewXLSXExporter(filename, options)
+	case Beancount, Ledger, HLedger:
+		exp = NewLedgerExporter(filename, options)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", options.Format)
+	}
+
+	if named, ok := exp.(interface{ setNaming(output.Naming) }); ok {
+		named.setNaming(options.Naming)
+	}
+	return exp, nil
+}
+
+// fileNaming is embedded by exporters writing one output file, to create it under the
+// naming rules of the run
+type fileNaming struct {
+	naming output.Naming
+}
+
+func (n *fileNaming) setNaming(naming output.Naming) {
+	n.naming = naming
+}
+
+// create starts writing the output file atomically under the naming rules
+func (n *fileNaming) create(filename string) (*output.File, error) {
+	return n.naming.Create(filename)
+}
+
+// NewStream creates a streaming exporter for the configured format. Formats that need
+// every transaction before writing, such as workbooks and ledgers, are buffered in memory.
+func NewStream(filename string, options Options) (StreamExporter, error) {
+	if options.Format == "" {
+		options.Format = InferFormat(filename)
+	}
+
+	exp, err := newExporter(filename, options)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, ok := exp.(StreamExporter)
+	if !ok {
+		stream = &bufferedExporter{Exporter: exp}
+	}
+	return &meteredStream{StreamExporter: stream, format: options.Format}, nil
+}
+
+// bufferedExporter adapts an Exporter to the streaming interface by collecting
+// transactions and exporting them on Close
+type bufferedExporter struct {
+	Exporter
+	transactions []*models.Transaction
+}
+
+// Open starts a new buffered export
+func (e *bufferedExporter) Open() error {
+	e.transactions = nil
+	return nil
+}
+
+// Write buffers a transaction
+func (e *bufferedExporter) Write(tx *models.Transaction) error {
+	e.transactions = append(e.transactions, tx)
+	return nil
+}
+
+// Close exports the buffered transactions
+func (e *bufferedExporter) Close() error {
+	return e.Export(e.transactions)
+}
+
+// Abort drops the buffered transactions without exporting them
+func (e *bufferedExporter) Abort() {
+	e.transactions = nil
+}
+
+// Summary returns a summary of the buffered transactions
+func (e *bufferedExporter) Summary() map[string]interface{} {
+	return e.GetExportSummary(e.transactions)
+}
+
+// ParseFormat validates a format name; an empty name means infer from the file extension
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case "", FormatCSV, FormatJSON, FormatNDJSON, FormatParquet, FormatSQLite, FormatXLSX, Beancount, Ledger, HLedger:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", name)
+	}
+}
+
+// InferFormat returns the format implied by a file extension, defaulting to CSV
+func InferFormat(filename string) Format {
+	if format, ok := extensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+	return FormatCSV
+}
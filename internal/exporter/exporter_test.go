@@ -0,0 +1,255 @@
+package exporter
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	testOwner = "0x1111111111111111111111111111111111111111"
+	testOther = "0x2222222222222222222222222222222222222222"
+	testToken = "0x5555555555555555555555555555555555555555"
+)
+
+// sampleTransactions returns an incoming ETH transfer and an outgoing token transfer
+func sampleTransactions() []*models.Transaction {
+	return []*models.Transaction{
+		{
+			Chain:            "ethereum",
+			Hash:             "0xaaa",
+			BlockNumber:      "18000000",
+			TransactionIndex: "4",
+			DateTime:         time.Date(2023, 12, 31, 22, 30, 0, 0, time.UTC),
+			FromAddress:      testOther,
+			ToAddress:        testOwner,
+			AssetSymbol:      "ETH",
+			AssetName:        "Ethereum",
+			AssetDecimals:    18,
+			Value:            big.NewInt(1500000000000000000),
+			ValueFormatted:   "1.5",
+			GasFeeWei:        big.NewInt(21000000000000),
+			GasFeeETH:        "0.000021",
+			TransactionType:  models.ETHTransfer,
+			Direction:        models.DirectionIn,
+			Status:           "Success",
+		},
+		{
+			Chain:             "ethereum",
+			Hash:              "0xbbb",
+			BlockNumber:       "18000100",
+			DateTime:          time.Date(2024, 1, 2, 9, 15, 0, 0, time.UTC),
+			FromAddress:       testOwner,
+			ToAddress:         testOther,
+			AssetContractAddr: testToken,
+			AssetSymbol:       "USDC",
+			AssetName:         "USD Coin",
+			AssetDecimals:     6,
+			Value:             big.NewInt(2500000),
+			ValueFormatted:    "2.5",
+			TransactionType:   models.ERC20Transfer,
+			Direction:         models.DirectionOut,
+			Status:            "Success",
+			LogIndex:          "12",
+		},
+	}
+}
+
+func TestJSONDocument(t *testing.T) {
+	for name, transactions := range map[string][]*models.Transaction{
+		"transactions": sampleTransactions(),
+		"empty":        nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "wallet.json")
+			if err := NewJSONExporter(filename).Export(transactions); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var document struct {
+				GeneratedAt  time.Time             `json:"generatedAt"`
+				Transactions []*models.Transaction `json:"transactions"`
+				Summary      struct {
+					TotalTransactions int            `json:"total_transactions"`
+					UniqueAssets      int            `json:"unique_assets"`
+					TransactionTypes  map[string]int `json:"transaction_types"`
+					Filename          string         `json:"filename"`
+				} `json:"summary"`
+			}
+			if err := json.Unmarshal(data, &document); err != nil {
+				t.Fatalf("invalid document: %v\n%s", err, data)
+			}
+
+			if document.GeneratedAt.IsZero() {
+				t.Error("document has no generation time")
+			}
+			if len(document.Transactions) != len(transactions) {
+				t.Fatalf("document holds %d transactions, want %d", len(document.Transactions), len(transactions))
+			}
+			for i, tx := range document.Transactions {
+				want := transactions[i]
+				if tx.Hash != want.Hash || tx.Value.Cmp(want.Value) != 0 || !tx.DateTime.Equal(want.DateTime) || tx.Direction != want.Direction {
+					t.Errorf("transaction %d read back as %+v", i, tx)
+				}
+			}
+
+			summary := document.Summary
+			if summary.TotalTransactions != len(transactions) || summary.UniqueAssets != len(transactions) || summary.Filename != filename {
+				t.Errorf("unexpected summary %+v", summary)
+			}
+			if len(transactions) > 0 && (summary.TransactionTypes[string(models.ETHTransfer)] != 1 || summary.TransactionTypes[string(models.ERC20Transfer)] != 1) {
+				t.Errorf("type counts %v", summary.TransactionTypes)
+			}
+		})
+	}
+}
+
+func TestNDJSONLines(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallet.ndjson")
+	if err := NewNDJSONExporter(filename).Export(sampleTransactions()); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var hashes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var tx models.Transaction
+		if err := json.Unmarshal(scanner.Bytes(), &tx); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		hashes = append(hashes, tx.Hash)
+	}
+	if len(hashes) != 2 || hashes[0] != "0xaaa" || hashes[1] != "0xbbb" {
+		t.Errorf("read back %v, want one line per transaction", hashes)
+	}
+}
+
+func TestParquetColumns(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallet.parquet")
+	if err := NewParquetExporter(filename).Export(sampleTransactions()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer data.Close()
+	info, err := data.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parquet.OpenFile(data, info.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.NumRows() != 2 {
+		t.Fatalf("file holds %d rows, want 2", file.NumRows())
+	}
+
+	// Columns are typed, not strings
+	schema := file.Schema()
+	types := map[string]string{
+		"date_time":         "TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS)",
+		"value":             "DECIMAL(38,18)",
+		"gas_fee":           "DECIMAL(38,18)",
+		"block_number":      "INT(64,true)",
+		"transaction_index": "INT(64,true)",
+		"asset_decimals":    "INT(32,true)",
+		"hash":              "STRING",
+	}
+	for column, want := range types {
+		leaf, ok := schema.Lookup(column)
+		if !ok {
+			t.Errorf("column %s missing", column)
+			continue
+		}
+		got := leaf.Node.Type().String()
+		if logical := leaf.Node.Type().LogicalType(); logical != nil {
+			got = logical.String()
+		}
+		if got != want {
+			t.Errorf("column %s is %s, want %s", column, got, want)
+		}
+	}
+
+	rows := make([]parquet.Row, 2)
+	reader := file.RowGroups()[0].Rows()
+	defer reader.Close()
+	if n, _ := reader.ReadRows(rows); n != 2 {
+		t.Fatalf("read %d rows, want 2", n)
+	}
+	value := func(row parquet.Row, column string) parquet.Value {
+		leaf, _ := schema.Lookup(column)
+		return row[leaf.ColumnIndex]
+	}
+
+	first, second := rows[0], rows[1]
+	if got := value(first, "date_time").Int64(); got != time.Date(2023, 12, 31, 22, 30, 0, 0, time.UTC).UnixMilli() {
+		t.Errorf("date_time %d", got)
+	}
+	if got := value(first, "block_number").Int64(); got != 18000000 {
+		t.Errorf("block_number %d", got)
+	}
+	if got := value(first, "transaction_index").Int64(); got != 4 {
+		t.Errorf("transaction_index %d", got)
+	}
+	if !value(second, "transaction_index").IsNull() || !value(first, "asset_contract_address").IsNull() {
+		t.Error("missing values are not null")
+	}
+	if got := value(second, "asset_contract_address").String(); got != testToken {
+		t.Errorf("asset_contract_address %s", got)
+	}
+
+	// Amounts are rescaled to 18 decimals whatever the asset decimals
+	decimals := map[string]struct {
+		row  parquet.Row
+		want string
+	}{
+		"ETH value":  {first, "1500000000000000000"},
+		"gas fee":    {first, "21000000000000"},
+		"USDC value": {second, "2500000000000000000"},
+	}
+	for name, tt := range decimals {
+		column := "value"
+		if name == "gas fee" {
+			column = "gas_fee"
+		}
+		got := new(big.Int).SetBytes(value(tt.row, column).ByteArray())
+		if got.String() != tt.want {
+			t.Errorf("%s unscaled %s, want %s", name, got, tt.want)
+		}
+	}
+}
+
+func TestParquetDecimalBounds(t *testing.T) {
+	encoded := parquetOptionalDecimal(big.NewInt(-1), 18).ByteArray()
+	for _, b := range encoded {
+		if b != 0xff {
+			t.Fatalf("-1 encoded as %x, want all ones", encoded)
+		}
+	}
+	if !parquetOptionalDecimal(new(big.Int).Exp(big.NewInt(10), big.NewInt(40), nil), 0).IsNull() {
+		t.Error("amount beyond the column precision is not null")
+	}
+	if !parquetOptionalDecimal(big.NewInt(1), 24).IsNull() {
+		t.Error("amount with more decimals than the column is not null")
+	}
+}
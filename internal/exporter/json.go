@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONExporter handles exporting transactions to a single JSON document of the form
+// {"generatedAt": ..., "transactions": [...], "summary": {...}}. The summary comes last
+// so the document can be written while transactions stream in.
+type JSONExporter struct {
+	fileNaming
+	filename string
+	file     *output.File
+	writer   *bufio.Writer
+	counter  *SummaryCounter
+}
+
+// NewJSONExporter creates a new JSON exporter
+func NewJSONExporter(filename string) *JSONExporter {
+	return &JSONExporter{
+		filename: filename,
+	}
+}
+
+// Export writes transactions as a JSON array alongside the export summary
+func (e *JSONExporter) Export(transactions []*models.Transaction) error {
+	if err := e.Open(); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		if err := e.Write(tx); err != nil {
+			e.Abort()
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// Open creates the JSON file and starts the document
+func (e *JSONExporter) Open() error {
+	file, err := e.create(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON file: %w", err)
+	}
+
+	e.file = file
+	e.writer = bufio.NewWriter(file)
+	e.counter = NewSummaryCounter()
+
+	generatedAt, _ := json.Marshal(time.Now().UTC())
+	fmt.Fprintf(e.writer, "{\n  \"generatedAt\": %s,\n  \"transactions\": [", generatedAt)
+	return nil
+}
+
+// Write appends one transaction to the transactions array
+func (e *JSONExporter) Write(tx *models.Transaction) error {
+	data, err := json.MarshalIndent(tx, "    ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to write JSON document: %w", err)
+	}
+
+	separator := ","
+	if e.counter.total == 0 {
+		separator = ""
+	}
+	if _, err := fmt.Fprintf(e.writer, "%s\n    %s", separator, data); err != nil {
+		return fmt.Errorf("failed to write JSON document: %w", err)
+	}
+
+	e.counter.Add(tx)
+	return nil
+}
+
+// Close writes the summary, ends the document and moves the file into place
+func (e *JSONExporter) Close() error {
+	if e.file == nil {
+		return nil
+	}
+	defer func() { e.file = nil }()
+
+	summary, err := json.MarshalIndent(e.Summary(), "  ", "  ")
+	if err != nil {
+		e.file.Abort()
+		return fmt.Errorf("failed to write JSON document: %w", err)
+	}
+
+	closing := "\n  ]"
+	if e.counter.total == 0 {
+		closing = "]"
+	}
+	fmt.Fprintf(e.writer, "%s,\n  \"summary\": %s\n}\n", closing, summary)
+
+	if err := e.writer.Flush(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	if err := e.file.Commit(); err != nil {
+		return fmt.Errorf("failed to save JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// Abort discards the partially written JSON file
+func (e *JSONExporter) Abort() {
+	if e.file != nil {
+		e.file.Abort()
+		e.file = nil
+	}
+}
+
+// Summary returns a summary of the transactions written since Open
+func (e *JSONExporter) Summary() map[string]interface{} {
+	return e.counter.Summary(e.filename)
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *JSONExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
+
+// NDJSONExporter handles exporting transactions as newline-delimited JSON
+type NDJSONExporter struct {
+	fileNaming
+	filename string
+	file     *output.File
+	writer   *bufio.Writer
+	encoder  *json.Encoder
+	counter  *SummaryCounter
+}
+
+// NewNDJSONExporter creates a new newline-delimited JSON exporter
+func NewNDJSONExporter(filename string) *NDJSONExporter {
+	return &NDJSONExporter{
+		filename: filename,
+	}
+}
+
+// Export writes one JSON object per transaction per line
+func (e *NDJSONExporter) Export(transactions []*models.Transaction) error {
+	if err := e.Open(); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		if err := e.Write(tx); err != nil {
+			e.Abort()
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// Open creates the NDJSON file
+func (e *NDJSONExporter) Open() error {
+	file, err := e.create(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to create NDJSON file: %w", err)
+	}
+
+	e.file = file
+	e.writer = bufio.NewWriter(file)
+	e.encoder = json.NewEncoder(e.writer)
+	e.counter = NewSummaryCounter()
+	return nil
+}
+
+// Write appends one transaction as a line of JSON
+func (e *NDJSONExporter) Write(tx *models.Transaction) error {
+	if err := e.encoder.Encode(tx); err != nil {
+		return fmt.Errorf("failed to write NDJSON record: %w", err)
+	}
+	e.counter.Add(tx)
+	return nil
+}
+
+// Close flushes the NDJSON file and moves it into place
+func (e *NDJSONExporter) Close() error {
+	if e.file == nil {
+		return nil
+	}
+	defer func() { e.file = nil }()
+
+	if err := e.writer.Flush(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("failed to write NDJSON file: %w", err)
+	}
+	if err := e.file.Commit(); err != nil {
+		return fmt.Errorf("failed to save NDJSON file: %w", err)
+	}
+
+	return nil
+}
+
+// Abort discards the partially written NDJSON file
+func (e *NDJSONExporter) Abort() {
+	if e.file != nil {
+		e.file.Abort()
+		e.file = nil
+	}
+}
+
+// Summary returns a summary of the transactions written since Open
+func (e *NDJSONExporter) Summary() map[string]interface{} {
+	return e.counter.Summary(e.filename)
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *NDJSONExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
@@ -0,0 +1,509 @@
+package exporter
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AccountTemplates defines account names with {chain}, {wallet}, {counterparty},
+// {symbol} and {type} placeholders
+type AccountTemplates struct {
+	Asset   string `json:"asset"`
+	Income  string `json:"income"`
+	Expense string `json:"expense"`
+	Fee     string `json:"fee"`
+}
+
+// DefaultAccountTemplates returns the default account naming scheme
+func DefaultAccountTemplates() AccountTemplates {
+	return AccountTemplates{
+		Asset:   "Assets:Crypto:{chain}:{wallet}",
+		Income:  "Income:Crypto:{counterparty}",
+		Expense: "Expenses:Crypto:{counterparty}",
+		Fee:     "Expenses:Fees:{chain}",
+	}
+}
+
+// LedgerExporter handles exporting transactions as balanced double-entry postings
+type LedgerExporter struct {
+	fileNaming
+	filename    string
+	options     Options
+	commodities map[string]*commodity
+	names       map[string]string
+	accounts    map[string]bool
+}
+
+// commodity describes an asset declared in the ledger
+type commodity struct {
+	name     string
+	symbol   string
+	fullName string
+	contract string
+	chain    string
+}
+
+// posting represents a single leg of a ledger entry
+type posting struct {
+	account   string
+	amount    string
+	commodity string
+	tokenID   string
+}
+
+// entry represents a balanced ledger transaction grouping all rows of one on-chain transaction
+type entry struct {
+	date     time.Time
+	hash     string
+	block    string
+	chain    string
+	payee    string
+	types    []string
+	postings []posting
+}
+
+// NewLedgerExporter creates a new double-entry accounting exporter
+func NewLedgerExporter(filename string, options Options) *LedgerExporter {
+	defaults := DefaultAccountTemplates()
+	if options.Accounts.Asset == "" {
+		options.Accounts.Asset = defaults.Asset
+	}
+	if options.Accounts.Income == "" {
+		options.Accounts.Income = defaults.Income
+	}
+	if options.Accounts.Expense == "" {
+		options.Accounts.Expense = defaults.Expense
+	}
+	if options.Accounts.Fee == "" {
+		options.Accounts.Fee = defaults.Fee
+	}
+	if options.Format == "" {
+		options.Format = Beancount
+	}
+
+	return &LedgerExporter{
+		filename: filename,
+		options:  options,
+	}
+}
+
+// Export writes transactions to a Beancount or Ledger/hledger journal
+func (e *LedgerExporter) Export(transactions []*models.Transaction) error {
+	e.commodities = make(map[string]*commodity)
+	e.names = make(map[string]string)
+	e.accounts = make(map[string]bool)
+
+	entries := e.buildEntries(transactions)
+
+	file, err := e.create(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to create ledger file: %w", err)
+	}
+	defer file.Abort()
+
+	writer := bufio.NewWriter(file)
+
+	openDate := time.Now().UTC()
+	if len(entries) > 0 {
+		openDate = entries[0].date
+	}
+
+	e.writeDeclarations(writer, openDate)
+	for _, en := range entries {
+		e.writeEntry(writer, en)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write ledger file: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save ledger file: %w", err)
+	}
+
+	return nil
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *LedgerExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
+
+// buildEntries groups transaction rows by hash and converts them to balanced entries
+func (e *LedgerExporter) buildEntries(transactions []*models.Transaction) []*entry {
+	var entries []*entry
+	byHash := make(map[string]*entry)
+
+	for _, tx := range transactions {
+		key := tx.Chain + "_" + tx.Hash
+		en, ok := byHash[key]
+		if !ok {
+			en = &entry{
+				date:  tx.DateTime,
+				hash:  tx.Hash,
+				block: tx.BlockNumber,
+				chain: tx.Chain,
+			}
+			byHash[key] = en
+			entries = append(entries, en)
+		}
+
+		e.addPostings(en, tx)
+	}
+
+	// Entries without postings only touched addresses outside the owned set
+	var result []*entry
+	for _, en := range entries {
+		if len(en.postings) > 0 {
+			result = append(result, en)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].date.Before(result[j].date)
+	})
+
+	return result
+}
+
+// addPostings appends the postings of a single transaction row to its entry
+func (e *LedgerExporter) addPostings(en *entry, tx *models.Transaction) {
+	from := strings.ToLower(tx.FromAddress)
+	to := strings.ToLower(tx.ToAddress)
+	fromOwned := e.options.Owned[from]
+	toOwned := e.options.Owned[to]
+
+	en.types = appendUnique(en.types, string(tx.TransactionType))
+
+	// Gas is paid by the sender of a normal transaction even when it fails
+	if fromOwned && isNormalTransaction(tx) && tx.GasFeeWei != nil && tx.GasFeeWei.Sign() > 0 {
+		native := e.nativeCommodity(tx.Chain)
+		fee := processor.FormatUnits(tx.GasFeeWei, processor.NativeDecimals)
+		en.postings = append(en.postings,
+			posting{account: e.account(e.options.Accounts.Fee, tx, from, to), amount: fee, commodity: native.name},
+			posting{account: e.account(e.options.Accounts.Asset, tx, from, to), amount: negate(fee), commodity: native.name},
+		)
+	}
+
+	if tx.Status == "Failed" || tx.Value == nil || tx.Value.Sign() == 0 || from == to {
+		return
+	}
+
+	com := e.commodityFor(tx)
+	amount := processor.FormatUnits(tx.Value, tx.AssetDecimals)
+
+	switch {
+	case fromOwned && toOwned:
+		en.postings = append(en.postings,
+			posting{account: e.account(e.options.Accounts.Asset, tx, to, from), amount: amount, commodity: com.name, tokenID: tx.TokenID},
+			posting{account: e.account(e.options.Accounts.Asset, tx, from, to), amount: negate(amount), commodity: com.name, tokenID: tx.TokenID},
+		)
+	case toOwned:
+		en.payee = e.label(from)
+		en.postings = append(en.postings,
+			posting{account: e.account(e.options.Accounts.Asset, tx, to, from), amount: amount, commodity: com.name, tokenID: tx.TokenID},
+			posting{account: e.account(e.options.Accounts.Income, tx, to, from), amount: negate(amount), commodity: com.name, tokenID: tx.TokenID},
+		)
+	case fromOwned:
+		en.payee = e.label(to)
+		en.postings = append(en.postings,
+			posting{account: e.account(e.options.Accounts.Expense, tx, from, to), amount: amount, commodity: com.name, tokenID: tx.TokenID},
+			posting{account: e.account(e.options.Accounts.Asset, tx, from, to), amount: negate(amount), commodity: com.name, tokenID: tx.TokenID},
+		)
+	}
+}
+
+// account renders an account template for a wallet and its counterparty
+func (e *LedgerExporter) account(template string, tx *models.Transaction, wallet, counterparty string) string {
+	replacer := strings.NewReplacer(
+		"{chain}", tx.Chain,
+		"{wallet}", e.label(wallet),
+		"{counterparty}", e.label(counterparty),
+		"{symbol}", tx.AssetSymbol,
+		"{type}", string(tx.TransactionType),
+	)
+
+	components := strings.Split(replacer.Replace(template), ":")
+	for i, component := range components {
+		components[i] = sanitizeAccountComponent(component)
+	}
+
+	account := strings.Join(components, ":")
+	e.accounts[account] = true
+	return account
+}
+
+// label returns the configured label for an address, or the address itself
+func (e *LedgerExporter) label(address string) string {
+	if label, ok := e.options.Labels[strings.ToLower(address)]; ok && label != "" {
+		return label
+	}
+	if address == "" {
+		return "Unknown"
+	}
+	return address
+}
+
+// nativeCommodity returns the commodity of the native asset of a chain
+func (e *LedgerExporter) nativeCommodity(chainName string) *commodity {
+	chain, err := models.LookupChain(chainName)
+	if err != nil {
+		chain = models.DefaultChain
+	}
+	return e.declare(chain.Name, "", nativeCommodityName(chain), chain.NativeSymbol, chain.NativeName)
+}
+
+// commodityFor returns the commodity of the asset moved by a transaction row
+func (e *LedgerExporter) commodityFor(tx *models.Transaction) *commodity {
+	switch tx.TransactionType {
+	case models.ERC20Transfer, models.ERC721Transfer, models.ERC1155Transfer:
+		return e.declare(tx.Chain, strings.ToLower(tx.AssetContractAddr), sanitizeCommodity(tx.AssetSymbol), tx.AssetSymbol, tx.AssetName)
+	default:
+		return e.nativeCommodity(tx.Chain)
+	}
+}
+
+// declare registers a commodity for a chain and contract, resolving name collisions. Tokens
+// never take the name of a native asset; a native whose name is taken anyway is qualified
+// with its chain.
+func (e *LedgerExporter) declare(chain, contract, name, symbol, fullName string) *commodity {
+	key := chain + "_" + contract
+	if com, ok := e.commodities[key]; ok {
+		return com
+	}
+
+	if name == "" && len(contract) >= 8 {
+		name = "T" + strings.ToUpper(contract[2:8])
+	}
+	owner, taken := e.names[name]
+	clash := taken && owner != key
+	switch {
+	case contract == "" && clash:
+		name = name + "-" + sanitizeCommodity(chain)
+	case contract != "" && (clash || isNativeCommodityName(name)) && len(contract) >= 8:
+		if len(name) > 17 {
+			name = name[:17]
+		}
+		name = name + "-" + strings.ToUpper(contract[2:8])
+	}
+	e.names[name] = key
+
+	com := &commodity{
+		name:     name,
+		symbol:   symbol,
+		fullName: fullName,
+		contract: contract,
+		chain:    chain,
+	}
+	e.commodities[key] = com
+	return com
+}
+
+// nativePrefixes are the short chain names qualifying natives that several chains share
+var nativePrefixes = map[string]string{
+	"arbitrum": "ARB",
+	"optimism": "OP",
+	"base":     "BASE",
+}
+
+// nativeCommodityName returns the commodity name of the native asset of a chain. A native
+// symbol shared with other chains, like ETH on the rollups, is kept bare on the default
+// chain only and prefixed with the chain elsewhere, e.g. ARB.ETH, so balances on different
+// chains never mix.
+func nativeCommodityName(chain models.Chain) string {
+	name := sanitizeCommodity(chain.NativeSymbol)
+	if chain.Name == models.DefaultChain.Name {
+		return name
+	}
+	for _, other := range models.Chains() {
+		if other.Name != chain.Name && strings.EqualFold(other.NativeSymbol, chain.NativeSymbol) {
+			prefix, ok := nativePrefixes[chain.Name]
+			if !ok {
+				prefix = sanitizeCommodity(chain.Name)
+			}
+			return prefix + "." + name
+		}
+	}
+	return name
+}
+
+// isNativeCommodityName reports whether a commodity name belongs to the native asset of a
+// supported chain
+func isNativeCommodityName(name string) bool {
+	for _, chain := range models.Chains() {
+		if nativeCommodityName(chain) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDeclarations writes commodity declarations and, for Beancount, account openings
+func (e *LedgerExporter) writeDeclarations(w *bufio.Writer, openDate time.Time) {
+	var commodities []*commodity
+	for _, com := range e.commodities {
+		commodities = append(commodities, com)
+	}
+	sort.Slice(commodities, func(i, j int) bool {
+		return commodities[i].name < commodities[j].name
+	})
+
+	var accounts []string
+	for account := range e.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	date := openDate.Format("2006-01-02")
+
+	switch e.options.Format {
+	case Beancount:
+		for _, com := range commodities {
+			fmt.Fprintf(w, "%s commodity %s\n", date, com.name)
+			fmt.Fprintf(w, "  name: %q\n", com.fullName)
+			if com.contract != "" {
+				fmt.Fprintf(w, "  contract: %q\n", com.contract)
+			}
+			fmt.Fprintf(w, "  chain: %q\n", com.chain)
+		}
+		fmt.Fprintln(w)
+		for _, account := range accounts {
+			fmt.Fprintf(w, "%s open %s\n", date, account)
+		}
+	default:
+		for _, com := range commodities {
+			fmt.Fprintf(w, "commodity %s\n", ledgerCommodity(com.name))
+			note := com.fullName
+			if com.contract != "" {
+				note = fmt.Sprintf("%s (%s on %s)", com.fullName, com.contract, com.chain)
+			}
+			fmt.Fprintf(w, "    ; %s\n", note)
+		}
+		fmt.Fprintln(w)
+		for _, account := range accounts {
+			fmt.Fprintf(w, "account %s\n", account)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeEntry writes a single balanced entry
+func (e *LedgerExporter) writeEntry(w *bufio.Writer, en *entry) {
+	date := en.date.Format("2006-01-02")
+	narration := strings.Join(en.types, ", ")
+
+	switch e.options.Format {
+	case Beancount:
+		fmt.Fprintf(w, "%s * %q %q\n", date, en.payee, narration)
+		fmt.Fprintf(w, "  txhash: %q\n", en.hash)
+		fmt.Fprintf(w, "  block: %q\n", en.block)
+		for _, p := range en.postings {
+			fmt.Fprintf(w, "  %-60s  %s %s\n", p.account, p.amount, p.commodity)
+			if p.tokenID != "" {
+				fmt.Fprintf(w, "    tokenid: %q\n", p.tokenID)
+			}
+		}
+	default:
+		payee := narration
+		if en.payee != "" {
+			payee = en.payee + " | " + narration
+		}
+		fmt.Fprintf(w, "%s * %s\n", date, payee)
+		fmt.Fprintf(w, "    ; txhash: %s\n", en.hash)
+		fmt.Fprintf(w, "    ; block: %s\n", en.block)
+		for _, p := range en.postings {
+			line := fmt.Sprintf("    %-60s  %s %s", p.account, p.amount, ledgerCommodity(p.commodity))
+			if p.tokenID != "" {
+				line += fmt.Sprintf("  ; tokenid: %s", p.tokenID)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// isNormalTransaction reports whether the row comes from the normal transaction list
+func isNormalTransaction(tx *models.Transaction) bool {
+	return tx.TransactionType == models.ETHTransfer || tx.TransactionType == models.ContractCall
+}
+
+// negate returns the negative of a decimal amount string
+func negate(amount string) string {
+	if strings.HasPrefix(amount, "-") {
+		return amount[1:]
+	}
+	if amount == "0" {
+		return amount
+	}
+	return "-" + amount
+}
+
+// appendUnique appends a value to a slice if it is not already present
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// sanitizeAccountComponent makes an account name component valid in Beancount and Ledger:
+// it must start with an uppercase letter or digit and contain only letters, digits and dashes
+func sanitizeAccountComponent(component string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(component) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	result := strings.TrimRight(b.String(), "-")
+	if result == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(result[:1]) + result[1:]
+}
+
+// sanitizeCommodity converts a token symbol into a valid Beancount commodity name:
+// uppercase letters, digits, dots, underscores and dashes, starting with a letter
+func sanitizeCommodity(symbol string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(symbol) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+
+	name := strings.TrimRight(b.String(), "._-")
+	if name == "" {
+		return ""
+	}
+	if name[0] < 'A' || name[0] > 'Z' {
+		name = "X" + name
+	}
+	if len(name) > 24 {
+		name = strings.TrimRight(name[:24], "._-")
+	}
+	return name
+}
+
+// ledgerCommodity quotes commodity names that Ledger and hledger would misparse
+func ledgerCommodity(name string) string {
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"testing"
+)
+
+func TestNativeCommoditiesPerChain(t *testing.T) {
+	e := NewLedgerExporter("", Options{})
+	e.commodities = make(map[string]*commodity)
+	e.names = make(map[string]string)
+
+	tests := []struct {
+		chain string
+		want  string
+	}{
+		{"ethereum", "ETH"},
+		{"arbitrum", "ARB.ETH"},
+		{"optimism", "OP.ETH"},
+		{"base", "BASE.ETH"},
+		{"polygon", "POL"},
+		{"bsc", "BNB"},
+	}
+	for _, tt := range tests {
+		if got := e.nativeCommodity(tt.chain).name; got != tt.want {
+			t.Errorf("native commodity on %s is %s, want %s", tt.chain, got, tt.want)
+		}
+	}
+	if got := e.nativeCommodity("ethereum"); got != e.nativeCommodity("") {
+		t.Error("the default chain declared its native twice")
+	}
+}
+
+func TestTokensNeverTakeNativeNames(t *testing.T) {
+	e := NewLedgerExporter("", Options{})
+	e.commodities = make(map[string]*commodity)
+	e.names = make(map[string]string)
+
+	// A token named like a native is declared before the native itself
+	fake := e.commodityFor(&models.Transaction{
+		Chain:             "ethereum",
+		TransactionType:   models.ERC20Transfer,
+		AssetContractAddr: "0xABCDEF0123456789abcdef0123456789abcdef01",
+		AssetSymbol:       "ETH",
+	})
+	native := e.nativeCommodity("ethereum")
+	if native.name != "ETH" {
+		t.Errorf("native is %s, want ETH", native.name)
+	}
+	if fake.name != "ETH-ABCDEF" {
+		t.Errorf("token is %s, want ETH-ABCDEF", fake.name)
+	}
+
+	usdcMainnet := e.commodityFor(&models.Transaction{
+		Chain:             "ethereum",
+		TransactionType:   models.ERC20Transfer,
+		AssetContractAddr: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		AssetSymbol:       "USDC",
+	})
+	usdcArbitrum := e.commodityFor(&models.Transaction{
+		Chain:             "arbitrum",
+		TransactionType:   models.ERC20Transfer,
+		AssetContractAddr: "0xaf88d065e77c8cc2239327c5edb3a432268e5831",
+		AssetSymbol:       "USDC",
+	})
+	if usdcMainnet.name != "USDC" || usdcArbitrum.name != "USDC-AF88D0" {
+		t.Errorf("tokens are %s and %s, want USDC and USDC-AF88D0", usdcMainnet.name, usdcArbitrum.name)
+	}
+}
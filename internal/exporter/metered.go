@@ -0,0 +1,77 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/metrics"
+	"crypto-acc-tracking/internal/models"
+	"time"
+)
+
+// meteredExporter records the rows and duration of every export in the metrics
+type meteredExporter struct {
+	Exporter
+	format Format
+}
+
+// Export exports the transactions and records the export
+func (e *meteredExporter) Export(transactions []*models.Transaction) error {
+	start := time.Now()
+	err := e.Exporter.Export(transactions)
+	observeExport(e.format, len(transactions), start, err)
+	return err
+}
+
+// meteredStream records the rows and duration of a streaming export, from Open to Close
+type meteredStream struct {
+	StreamExporter
+	format Format
+	start  time.Time
+	rows   int
+}
+
+// Open starts the export and its clock
+func (e *meteredStream) Open() error {
+	e.start = time.Now()
+	e.rows = 0
+	return e.StreamExporter.Open()
+}
+
+// Write writes a transaction and counts it
+func (e *meteredStream) Write(tx *models.Transaction) error {
+	if err := e.StreamExporter.Write(tx); err != nil {
+		return err
+	}
+	e.rows++
+	return nil
+}
+
+// Close completes the export and records it
+func (e *meteredStream) Close() error {
+	err := e.StreamExporter.Close()
+	observeExport(e.format, e.rows, e.start, err)
+	return err
+}
+
+// Abort discards the export and records it as failed
+func (e *meteredStream) Abort() {
+	e.StreamExporter.Abort()
+	metrics.ExportDuration.WithLabelValues(string(e.format), "failure").Observe(time.Since(e.start).Seconds())
+}
+
+// Export exports the transactions in one go and records the export
+func (e *meteredStream) Export(transactions []*models.Transaction) error {
+	start := time.Now()
+	err := e.StreamExporter.Export(transactions)
+	observeExport(e.format, len(transactions), start, err)
+	return err
+}
+
+// observeExport records a finished export; rows only count when it succeeded
+func observeExport(format Format, rows int, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	} else {
+		metrics.ExportedRows.WithLabelValues(string(format)).Add(float64(rows))
+	}
+	metrics.ExportDuration.WithLabelValues(string(format), result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,243 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+)
+
+const (
+	// Amounts are stored as DECIMAL(38,18) in 16-byte fixed length arrays
+	parquetDecimalScale     = 18
+	parquetDecimalPrecision = 38
+	parquetDecimalSize      = 16
+
+	// Rows per row group, which bounds the rows the writer holds in memory
+	parquetRowGroupSize = 50000
+)
+
+// parquetSchema describes the typed columns of a transaction export
+var parquetSchema = parquet.NewSchema("transaction", parquet.Group{
+	"hash":                   parquet.String(),
+	"date_time":              parquet.Timestamp(parquet.Millisecond),
+	"from_address":           parquet.String(),
+	"to_address":             parquet.String(),
+	"transaction_type":       parquet.String(),
+	"asset_contract_address": parquet.Optional(parquet.String()),
+	"asset_symbol":           parquet.Optional(parquet.String()),
+	"asset_name":             parquet.Optional(parquet.String()),
+	"asset_decimals":         parquet.Int(32),
+	"token_id":               parquet.Optional(parquet.String()),
+	"value":                  parquet.Optional(parquetDecimal()),
+	"value_raw":              parquet.String(),
+	"gas_fee":                parquet.Optional(parquetDecimal()),
+	"block_number":           parquet.Int(64),
+	"transaction_index":      parquet.Optional(parquet.Int(64)),
+	"status":                 parquet.String(),
+	"chain":                  parquet.String(),
+	"direction":              parquet.Optional(parquet.String()),
+})
+
+// ParquetExporter handles exporting transactions to a Parquet file with typed columns
+type ParquetExporter struct {
+	fileNaming
+	filename string
+	file     *output.File
+	writer   *parquet.Writer
+	counter  *SummaryCounter
+}
+
+// NewParquetExporter creates a new Parquet exporter
+func NewParquetExporter(filename string) *ParquetExporter {
+	return &ParquetExporter{
+		filename: filename,
+	}
+}
+
+// Export writes transactions to a snappy-compressed Parquet file
+func (e *ParquetExporter) Export(transactions []*models.Transaction) error {
+	if err := e.Open(); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		if err := e.Write(tx); err != nil {
+			e.Abort()
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// Open creates the Parquet file
+func (e *ParquetExporter) Open() error {
+	file, err := e.create(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+
+	e.file = file
+	e.writer = parquet.NewWriter(file, parquetSchema,
+		parquet.Compression(&snappy.Codec{}),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+	)
+	e.counter = NewSummaryCounter()
+	return nil
+}
+
+// Write appends one transaction; rows are buffered into row groups by the writer
+func (e *ParquetExporter) Write(tx *models.Transaction) error {
+	if _, err := e.writer.WriteRows([]parquet.Row{parquetRow(tx)}); err != nil {
+		return fmt.Errorf("failed to write Parquet rows: %w", err)
+	}
+	e.counter.Add(tx)
+	return nil
+}
+
+// Close writes the file footer and moves the Parquet file into place
+func (e *ParquetExporter) Close() error {
+	if e.file == nil {
+		return nil
+	}
+	defer func() { e.file = nil }()
+
+	if err := e.writer.Close(); err != nil {
+		e.file.Abort()
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	if err := e.file.Commit(); err != nil {
+		return fmt.Errorf("failed to save Parquet file: %w", err)
+	}
+
+	return nil
+}
+
+// Abort discards the partially written Parquet file
+func (e *ParquetExporter) Abort() {
+	if e.file != nil {
+		e.file.Abort()
+		e.file = nil
+	}
+}
+
+// Summary returns a summary of the transactions written since Open
+func (e *ParquetExporter) Summary() map[string]interface{} {
+	return e.counter.Summary(e.filename)
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *ParquetExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
+
+// parquetRow converts a transaction into a row ordered by the schema columns
+func parquetRow(tx *models.Transaction) parquet.Row {
+	values := map[string]parquet.Value{
+		"hash":                   parquetString(tx.Hash),
+		"date_time":              parquet.Int64Value(tx.DateTime.UnixMilli()),
+		"from_address":           parquetString(tx.FromAddress),
+		"to_address":             parquetString(tx.ToAddress),
+		"transaction_type":       parquetString(string(tx.TransactionType)),
+		"asset_contract_address": parquetOptionalString(tx.AssetContractAddr),
+		"asset_symbol":           parquetOptionalString(tx.AssetSymbol),
+		"asset_name":             parquetOptionalString(tx.AssetName),
+		"asset_decimals":         parquet.Int32Value(int32(tx.AssetDecimals)),
+		"token_id":               parquetOptionalString(tx.TokenID),
+		"value":                  parquetOptionalDecimal(tx.Value, tx.AssetDecimals),
+		"value_raw":              parquetString(bigString(tx.Value)),
+		"gas_fee":                parquetOptionalDecimal(tx.GasFeeWei, processor.NativeDecimals),
+		"block_number":           parquet.Int64Value(parseInt64(tx.BlockNumber)),
+		"transaction_index":      parquetOptionalInt64(tx.TransactionIndex),
+		"status":                 parquetString(tx.Status),
+		"chain":                  parquetString(tx.Chain),
+		"direction":              parquetOptionalString(string(tx.Direction)),
+	}
+
+	columns := parquetSchema.Columns()
+	row := make(parquet.Row, len(columns))
+	for i, path := range columns {
+		leaf, _ := parquetSchema.Lookup(path...)
+		value := values[path[0]]
+
+		definitionLevel := 0
+		if leaf.Node.Optional() && !value.IsNull() {
+			definitionLevel = 1
+		}
+		row[i] = value.Level(0, definitionLevel, i)
+	}
+
+	return row
+}
+
+// parquetDecimal returns the node used for amount columns
+func parquetDecimal() parquet.Node {
+	return parquet.Decimal(parquetDecimalScale, parquetDecimalPrecision, parquet.FixedLenByteArrayType(parquetDecimalSize))
+}
+
+// parquetString returns a required string value
+func parquetString(s string) parquet.Value {
+	return parquet.ByteArrayValue([]byte(s))
+}
+
+// parquetOptionalString returns a string value, or null when empty
+func parquetOptionalString(s string) parquet.Value {
+	if s == "" {
+		return parquet.NullValue()
+	}
+	return parquetString(s)
+}
+
+// parquetOptionalInt64 returns an integer value, or null when it cannot be parsed
+func parquetOptionalInt64(s string) parquet.Value {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return parquet.NullValue()
+	}
+	return parquet.Int64Value(n)
+}
+
+// parquetOptionalDecimal rescales a raw amount to the decimal column scale and encodes it
+// as big-endian two's complement; amounts that do not fit the column are written as null
+func parquetOptionalDecimal(raw *big.Int, decimals int) parquet.Value {
+	if raw == nil || decimals > parquetDecimalScale {
+		return parquet.NullValue()
+	}
+
+	unscaled := new(big.Int).Mul(raw, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(parquetDecimalScale-decimals)), nil))
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(parquetDecimalPrecision), nil)
+	if new(big.Int).Abs(unscaled).Cmp(limit) >= 0 {
+		return parquet.NullValue()
+	}
+
+	encoded := make([]byte, parquetDecimalSize)
+	if unscaled.Sign() >= 0 {
+		unscaled.FillBytes(encoded)
+	} else {
+		// Two's complement of a negative amount
+		modulus := new(big.Int).Lsh(big.NewInt(1), parquetDecimalSize*8)
+		new(big.Int).Add(modulus, unscaled).FillBytes(encoded)
+	}
+
+	return parquet.FixedLenByteArrayValue(encoded)
+}
+
+// bigString returns the base 10 representation of a possibly nil integer
+func bigString(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+	return value.String()
+}
+
+// parseInt64 parses a base 10 integer, returning zero when it is invalid
+func parseInt64(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
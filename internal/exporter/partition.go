@@ -0,0 +1,109 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Partition selects how an export is split into one file per group of transactions
+type Partition string
+
+const (
+	PartitionNone         Partition = ""
+	PartitionYear         Partition = "year"
+	PartitionMonth        Partition = "month"
+	PartitionAsset        Partition = "asset"
+	PartitionType         Partition = "type"
+	PartitionCounterparty Partition = "counterparty"
+)
+
+// DefaultSplitTemplate names partition files after the output file, e.g. wallet-2024.csv
+const DefaultSplitTemplate = "{name}-{key}{ext}"
+
+// ParsePartition resolves a --split-by value
+func ParsePartition(name string) (Partition, error) {
+	switch p := Partition(strings.ToLower(strings.TrimSpace(name))); p {
+	case PartitionNone, PartitionYear, PartitionMonth, PartitionAsset, PartitionType, PartitionCounterparty:
+		return p, nil
+	}
+	return "", fmt.Errorf("unsupported split %q (supported: year, month, asset, type, counterparty)", name)
+}
+
+// ValidateSplitTemplate checks that a template gives every partition its own file
+func ValidateSplitTemplate(template string) error {
+	if !strings.Contains(template, "{key}") {
+		return fmt.Errorf("split template %q must contain {key}", template)
+	}
+	return nil
+}
+
+// PartitionKey returns the partition a transaction belongs to. Years and months follow
+// the time zone of the CSV schema, so tax years match the dates in the file.
+func (o Options) PartitionKey(tx *models.Transaction) string {
+	var key string
+	switch o.SplitBy {
+	case PartitionYear, PartitionMonth:
+		location := time.UTC
+		if o.CSV != nil && o.CSV.location != nil {
+			location = o.CSV.location
+		}
+		layout := "2006"
+		if o.SplitBy == PartitionMonth {
+			layout = "2006-01"
+		}
+		key = tx.DateTime.In(location).Format(layout)
+	case PartitionAsset:
+		// Symbols are not unique, so tokens are told apart by their contract
+		key = tx.AssetSymbol
+		if contract := strings.ToLower(tx.AssetContractAddr); len(contract) >= 10 {
+			key += "-" + contract[:10]
+		}
+	case PartitionType:
+		key = string(tx.TransactionType)
+	case PartitionCounterparty:
+		key = strings.ToLower(tx.FromAddress)
+		if o.Owned[key] {
+			key = strings.ToLower(tx.ToAddress)
+		}
+	}
+
+	return sanitizeKey(key)
+}
+
+// PartitionFile returns the file a partition is written to. The template may use {name}
+// (the output file name without extension), {ext} and {key}, and is relative to the
+// directory of the output file.
+func (o Options) PartitionFile(outputFile, key string) string {
+	template := o.SplitTemplate
+	if template == "" {
+		template = DefaultSplitTemplate
+	}
+
+	ext := filepath.Ext(outputFile)
+	name := strings.TrimSuffix(filepath.Base(outputFile), ext)
+	filename := strings.NewReplacer("{name}", name, "{ext}", ext, "{key}", key).Replace(template)
+
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(filepath.Dir(outputFile), filename)
+}
+
+// sanitizeKey makes a partition key safe to use in a file name
+func sanitizeKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(key))
+
+	if strings.Trim(key, "._") == "" {
+		return "unknown"
+	}
+	return key
+}
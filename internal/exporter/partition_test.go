@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPartitionKey(t *testing.T) {
+	eth, usdc := sampleTransactions()[0], sampleTransactions()[1]
+	berlin := DefaultCSVSchema()
+	berlin.TimeZone = "Europe/Berlin"
+	if err := berlin.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	owned := map[string]bool{testOwner: true}
+
+	tests := []struct {
+		name    string
+		options Options
+		tx      *models.Transaction
+		want    string
+	}{
+		{"year", Options{SplitBy: PartitionYear}, eth, "2023"},
+		{"year in the CSV time zone", Options{SplitBy: PartitionYear, CSV: berlin}, eth, "2023"},
+		{"month", Options{SplitBy: PartitionMonth}, usdc, "2024-01"},
+		{"native asset", Options{SplitBy: PartitionAsset}, eth, "ETH"},
+		{"token", Options{SplitBy: PartitionAsset}, usdc, "USDC-0x55555555"},
+		{"type", Options{SplitBy: PartitionType}, usdc, "ERC-20_Transfer"},
+		{"incoming counterparty", Options{SplitBy: PartitionCounterparty, Owned: owned}, eth, testOther},
+		{"outgoing counterparty", Options{SplitBy: PartitionCounterparty, Owned: owned}, usdc, testOther},
+		{"no asset", Options{SplitBy: PartitionAsset}, &models.Transaction{}, "unknown"},
+		{"unsafe symbol", Options{SplitBy: PartitionAsset}, &models.Transaction{AssetSymbol: "../US$"}, ".._US_"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.options.PartitionKey(tt.tx); got != tt.want {
+			t.Errorf("%s: key %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// New Year's Eve 23:30 UTC is already the next year in Berlin
+	late := &models.Transaction{DateTime: time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC)}
+	if got := (Options{SplitBy: PartitionYear, CSV: berlin}).PartitionKey(late); got != "2024" {
+		t.Errorf("year in Berlin %q, want 2024", got)
+	}
+	if got := (Options{SplitBy: PartitionYear}).PartitionKey(late); got != "2023" {
+		t.Errorf("year in UTC %q, want 2023", got)
+	}
+}
+
+func TestPartitionFile(t *testing.T) {
+	dir := filepath.Join("exports", "2024")
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", filepath.Join(dir, "wallet-ETH.csv")},
+		{"{key}/{name}{ext}", filepath.Join(dir, "ETH", "wallet.csv")},
+		{"{name}_{key}.txt", filepath.Join(dir, "wallet_ETH.txt")},
+		{"/tmp/{key}{ext}", "/tmp/ETH.csv"},
+	}
+	for _, tt := range tests {
+		options := Options{SplitBy: PartitionAsset, SplitTemplate: tt.template}
+		if got := options.PartitionFile(filepath.Join(dir, "wallet.csv"), "ETH"); got != tt.want {
+			t.Errorf("template %q: %s, want %s", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestParsePartition(t *testing.T) {
+	for _, name := range []string{"", "year", "Month", " asset ", "type", "counterparty"} {
+		if _, err := ParsePartition(name); err != nil {
+			t.Errorf("%q rejected: %v", name, err)
+		}
+	}
+	if _, err := ParsePartition("week"); err == nil {
+		t.Error("unknown split accepted")
+	}
+	if err := ValidateSplitTemplate("{name}{ext}"); err == nil {
+		t.Error("template without {key} accepted")
+	}
+}
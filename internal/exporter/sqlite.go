@@ -0,0 +1,442 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" driver
+)
+
+// sqliteSchema creates the normalized tables and their indexes
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	exported_at       TEXT NOT NULL,
+	addresses         TEXT NOT NULL,
+	transaction_count INTEGER NOT NULL,
+	transfer_count    INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS addresses (
+	address TEXT PRIMARY KEY,
+	label   TEXT,
+	owned   INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS assets (
+	chain            TEXT NOT NULL,
+	contract_address TEXT NOT NULL,
+	symbol           TEXT,
+	name             TEXT,
+	decimals         INTEGER,
+	PRIMARY KEY (chain, contract_address)
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+	chain             TEXT NOT NULL,
+	hash              TEXT NOT NULL,
+	block_number      INTEGER NOT NULL,
+	transaction_index INTEGER,
+	timestamp         INTEGER NOT NULL,
+	date_time         TEXT NOT NULL,
+	status            TEXT,
+	gas_fee_wei       TEXT NOT NULL DEFAULT '0',
+	gas_fee           TEXT NOT NULL DEFAULT '0',
+	PRIMARY KEY (chain, hash)
+);
+
+CREATE TABLE IF NOT EXISTS transfers (
+	id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+	chain                  TEXT NOT NULL,
+	hash                   TEXT NOT NULL,
+	transaction_type       TEXT NOT NULL,
+	from_address           TEXT NOT NULL,
+	to_address             TEXT NOT NULL,
+	asset_contract_address TEXT NOT NULL,
+	token_id               TEXT NOT NULL,
+	value_raw              TEXT NOT NULL,
+	value                  TEXT NOT NULL,
+	status                 TEXT,
+	direction              TEXT,
+	log_index              TEXT NOT NULL DEFAULT '',
+	UNIQUE (chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw, log_index),
+	FOREIGN KEY (chain, hash) REFERENCES transactions (chain, hash),
+	FOREIGN KEY (chain, asset_contract_address) REFERENCES assets (chain, contract_address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_block ON transactions (block_number);
+CREATE INDEX IF NOT EXISTS idx_transactions_timestamp ON transactions (timestamp);
+CREATE INDEX IF NOT EXISTS idx_transfers_hash ON transfers (chain, hash);
+CREATE INDEX IF NOT EXISTS idx_transfers_from ON transfers (from_address);
+CREATE INDEX IF NOT EXISTS idx_transfers_to ON transfers (to_address);
+`
+
+const (
+	upsertAddress = `
+INSERT INTO addresses (address, label, owned) VALUES (?, ?, ?)
+ON CONFLICT (address) DO UPDATE SET
+	label = COALESCE(excluded.label, addresses.label),
+	owned = MAX(excluded.owned, addresses.owned)`
+
+	upsertAsset = `
+INSERT INTO assets (chain, contract_address, symbol, name, decimals) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (chain, contract_address) DO UPDATE SET
+	symbol = excluded.symbol,
+	name = excluded.name,
+	decimals = excluded.decimals`
+
+	upsertTransaction = `
+INSERT INTO transactions (chain, hash, block_number, transaction_index, timestamp, date_time, status, gas_fee_wei, gas_fee)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (chain, hash) DO UPDATE SET
+	block_number = excluded.block_number,
+	transaction_index = COALESCE(excluded.transaction_index, transactions.transaction_index),
+	timestamp = excluded.timestamp,
+	date_time = excluded.date_time,
+	status = COALESCE(excluded.status, transactions.status),
+	gas_fee_wei = CASE WHEN excluded.gas_fee_wei != '0' THEN excluded.gas_fee_wei ELSE transactions.gas_fee_wei END,
+	gas_fee = CASE WHEN excluded.gas_fee_wei != '0' THEN excluded.gas_fee ELSE transactions.gas_fee END`
+
+	// claimTransfer gives a transfer row written before log indexes were stored the log
+	// index of the same transfer, so re-exporting into a migrated database updates it
+	claimTransfer = `
+UPDATE transfers SET log_index = ?
+WHERE log_index = '' AND chain = ? AND hash = ? AND transaction_type = ? AND from_address = ? AND to_address = ?
+	AND asset_contract_address = ? AND token_id = ? AND value_raw = ?`
+
+	upsertTransfer = `
+INSERT INTO transfers (chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw, value, status, direction, log_index)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw, log_index) DO UPDATE SET
+	value = excluded.value,
+	status = excluded.status,
+	direction = excluded.direction`
+)
+
+// SQLiteExporter handles exporting transactions to a SQLite database with normalized tables
+type SQLiteExporter struct {
+	filename   string
+	file       *output.File // Temporary database committed under the naming rules, if any
+	options    Options
+	db         *sql.DB
+	tx         *sql.Tx
+	statements map[string]*sql.Stmt
+	addresses  map[string]bool
+	hashes     map[string]bool
+	counter    *SummaryCounter
+}
+
+// NewSQLiteExporter creates a new SQLite exporter
+func NewSQLiteExporter(filename string, options Options) *SQLiteExporter {
+	return &SQLiteExporter{
+		filename: filename,
+		options:  options,
+	}
+}
+
+// Export upserts transactions into the database, creating the schema if needed.
+// Re-running into the same file updates existing rows instead of duplicating them.
+func (e *SQLiteExporter) Export(transactions []*models.Transaction) error {
+	if err := e.Open(); err != nil {
+		return err
+	}
+
+	for _, t := range transactions {
+		if err := e.Write(t); err != nil {
+			e.rollback()
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// Open opens the database, creates the schema and starts the transaction all rows are
+// written in. With rotation or NoClobber a new database is built in a temporary file and
+// only moved into place by Close; otherwise rows are upserted into the existing file.
+func (e *SQLiteExporter) Open() error {
+	path := e.filename
+	if naming := e.options.Naming; naming.NoClobber || naming.Rotate > 0 {
+		file, err := naming.Create(e.filename)
+		if err != nil {
+			return fmt.Errorf("failed to create SQLite database: %w", err)
+		}
+		e.file = file
+		path = file.Name()
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		e.abortFile()
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	e.db = db
+
+	if err := migrateTransfers(db); err != nil {
+		e.rollback()
+		return fmt.Errorf("failed to migrate SQLite schema: %w", err)
+	}
+
+	e.tx, err = db.Begin()
+	if err != nil {
+		e.rollback()
+		return fmt.Errorf("failed to begin SQLite transaction: %w", err)
+	}
+
+	e.statements = make(map[string]*sql.Stmt)
+	for _, query := range []string{upsertAddress, upsertAsset, upsertTransaction, claimTransfer, upsertTransfer} {
+		stmt, err := e.tx.Prepare(query)
+		if err != nil {
+			e.rollback()
+			return fmt.Errorf("failed to prepare SQLite statement: %w", err)
+		}
+		e.statements[query] = stmt
+	}
+
+	e.addresses = make(map[string]bool)
+	e.hashes = make(map[string]bool)
+	e.counter = NewSummaryCounter()
+	for address := range e.options.Owned {
+		e.addresses[strings.ToLower(address)] = true
+	}
+
+	return nil
+}
+
+// migrateTransfers creates the schema, first rebuilding a transfers table written before
+// log_index was part of its unique key so identical transfers of one transaction can coexist
+func migrateTransfers(db *sql.DB) error {
+	var legacy bool
+	err := db.QueryRow(`SELECT COUNT(*) = 0 AND EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'transfers')
+FROM pragma_table_info('transfers') WHERE name = 'log_index'`).Scan(&legacy)
+	if err != nil {
+		return err
+	}
+	if !legacy {
+		_, err := db.Exec(sqliteSchema)
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	columns := "id, chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw, value, status, direction"
+	for _, query := range []string{
+		"ALTER TABLE transfers RENAME TO transfers_legacy",
+		"DROP INDEX IF EXISTS idx_transfers_hash",
+		"DROP INDEX IF EXISTS idx_transfers_from",
+		"DROP INDEX IF EXISTS idx_transfers_to",
+		sqliteSchema,
+		"INSERT INTO transfers (" + columns + ") SELECT " + columns + " FROM transfers_legacy",
+		"DROP TABLE transfers_legacy",
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Write upserts the asset, transaction and transfer rows of one transaction
+func (e *SQLiteExporter) Write(t *models.Transaction) error {
+	from := strings.ToLower(t.FromAddress)
+	to := strings.ToLower(t.ToAddress)
+	contract := strings.ToLower(t.AssetContractAddr)
+	if isNativeTransfer(t) {
+		contract = ""
+	}
+	e.addresses[from] = true
+	e.addresses[to] = true
+	e.hashes[t.Chain+"_"+t.Hash] = true
+
+	if _, err := e.statements[upsertAsset].Exec(t.Chain, contract, t.AssetSymbol, t.AssetName, t.AssetDecimals); err != nil {
+		return fmt.Errorf("failed to write asset %s: %w", contract, err)
+	}
+
+	var status interface{}
+	if isNormalTransaction(t) {
+		status = t.Status
+	}
+	var index interface{}
+	if n, err := strconv.ParseInt(t.TransactionIndex, 10, 64); err == nil {
+		index = n
+	}
+	gasFeeWei := bigString(t.GasFeeWei)
+	if _, err := e.statements[upsertTransaction].Exec(
+		t.Chain,
+		t.Hash,
+		parseInt64(t.BlockNumber),
+		index,
+		t.DateTime.Unix(),
+		t.DateTime.UTC().Format(time.RFC3339),
+		status,
+		gasFeeWei,
+		t.GasFeeETH,
+	); err != nil {
+		return fmt.Errorf("failed to write transaction %s: %w", t.Hash, err)
+	}
+
+	if t.LogIndex != "" {
+		if _, err := e.statements[claimTransfer].Exec(
+			t.LogIndex,
+			t.Chain,
+			t.Hash,
+			string(t.TransactionType),
+			from,
+			to,
+			contract,
+			t.TokenID,
+			bigString(t.Value),
+		); err != nil {
+			return fmt.Errorf("failed to write transfer %s: %w", t.Hash, err)
+		}
+	}
+	if _, err := e.statements[upsertTransfer].Exec(
+		t.Chain,
+		t.Hash,
+		string(t.TransactionType),
+		from,
+		to,
+		contract,
+		t.TokenID,
+		bigString(t.Value),
+		t.ValueFormatted,
+		t.Status,
+		string(t.Direction),
+		t.LogIndex,
+	); err != nil {
+		return fmt.Errorf("failed to write transfer %s: %w", t.Hash, err)
+	}
+
+	e.counter.Add(t)
+	return nil
+}
+
+// Close upserts the addresses and the run record, then commits and closes the database
+func (e *SQLiteExporter) Close() error {
+	if e.db == nil {
+		return nil
+	}
+
+	if err := e.writeAddresses(); err != nil {
+		e.rollback()
+		return err
+	}
+
+	for _, stmt := range e.statements {
+		stmt.Close()
+	}
+	if err := e.tx.Commit(); err != nil {
+		e.rollback()
+		return fmt.Errorf("failed to commit SQLite transaction: %w", err)
+	}
+
+	err := e.db.Close()
+	e.db = nil
+	e.tx = nil
+	e.statements = nil
+	if err != nil {
+		e.abortFile()
+		return fmt.Errorf("failed to close SQLite database: %w", err)
+	}
+
+	if e.file != nil {
+		file := e.file
+		e.file = nil
+		return file.Commit()
+	}
+	return nil
+}
+
+// Summary returns a summary of the transactions written since Open
+func (e *SQLiteExporter) Summary() map[string]interface{} {
+	return e.counter.Summary(e.filename)
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *SQLiteExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
+
+// writeAddresses upserts every address seen and records the run
+func (e *SQLiteExporter) writeAddresses() error {
+	for address := range e.addresses {
+		if address == "" {
+			continue
+		}
+		var label interface{}
+		if l, ok := e.options.Labels[address]; ok && l != "" {
+			label = l
+		}
+		owned := 0
+		if e.options.Owned[address] {
+			owned = 1
+		}
+		if _, err := e.statements[upsertAddress].Exec(address, label, owned); err != nil {
+			return fmt.Errorf("failed to write address %s: %w", address, err)
+		}
+	}
+
+	var owned []string
+	for address := range e.options.Owned {
+		owned = append(owned, strings.ToLower(address))
+	}
+	sort.Strings(owned)
+
+	if _, err := e.tx.Exec(
+		"INSERT INTO runs (exported_at, addresses, transaction_count, transfer_count) VALUES (?, ?, ?, ?)",
+		time.Now().UTC().Format(time.RFC3339),
+		strings.Join(owned, ","),
+		len(e.hashes),
+		e.counter.total,
+	); err != nil {
+		return fmt.Errorf("failed to write run metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Abort rolls back everything written since Open, leaving the database as it was
+func (e *SQLiteExporter) Abort() {
+	e.rollback()
+}
+
+// rollback abandons the open transaction and closes the database
+func (e *SQLiteExporter) rollback() {
+	if e.db == nil {
+		return
+	}
+	for _, stmt := range e.statements {
+		stmt.Close()
+	}
+	if e.tx != nil {
+		e.tx.Rollback()
+	}
+	e.db.Close()
+	e.db = nil
+	e.tx = nil
+	e.statements = nil
+	e.abortFile()
+}
+
+// abortFile discards the temporary database, if any, leaving the existing file in place
+func (e *SQLiteExporter) abortFile() {
+	if e.file != nil {
+		e.file.Abort()
+		e.file = nil
+	}
+}
+
+// isNativeTransfer reports whether the row moves the native asset of the chain
+func isNativeTransfer(tx *models.Transaction) bool {
+	return isNormalTransaction(tx) || tx.TransactionType == models.InternalTx
+}
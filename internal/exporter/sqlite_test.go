@@ -0,0 +1,119 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// payouts returns two identical token transfers of one transaction told apart by log index
+func payouts() []*models.Transaction {
+	var transactions []*models.Transaction
+	for _, logIndex := range []string{"7", "8"} {
+		transactions = append(transactions, &models.Transaction{
+			Chain:             "ethereum",
+			Hash:              "0xpayout",
+			BlockNumber:       "100",
+			DateTime:          time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			FromAddress:       "0x2222222222222222222222222222222222222222",
+			ToAddress:         "0x1111111111111111111111111111111111111111",
+			AssetContractAddr: "0x5555555555555555555555555555555555555555",
+			AssetSymbol:       "USDC",
+			AssetDecimals:     6,
+			Value:             big.NewInt(1000000),
+			ValueFormatted:    "1",
+			TransactionType:   models.ERC20Transfer,
+			Direction:         models.DirectionIn,
+			Status:            "Success",
+			LogIndex:          logIndex,
+		})
+	}
+	return transactions
+}
+
+// transferLogIndexes returns the log index of every transfer row in the database
+func transferLogIndexes(t *testing.T, filename string) []string {
+	t.Helper()
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT log_index FROM transfers ORDER BY log_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			t.Fatal(err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+func TestSQLiteKeepsTransfersWithDistinctLogIndexes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallet.db")
+
+	// The second export upserts the same rows instead of adding them again
+	for run := 0; run < 2; run++ {
+		if err := NewSQLiteExporter(filename, Options{}).Export(payouts()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := transferLogIndexes(t, filename); len(got) != 2 || got[0] != "7" || got[1] != "8" {
+		t.Errorf("log indexes %v, want [7 8]", got)
+	}
+}
+
+func TestSQLiteMigratesTransfersWithoutLogIndex(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallet.db")
+
+	// A database written before log indexes were stored holds one of the two payouts
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		`CREATE TABLE transfers (
+			id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+			chain                  TEXT NOT NULL,
+			hash                   TEXT NOT NULL,
+			transaction_type       TEXT NOT NULL,
+			from_address           TEXT NOT NULL,
+			to_address             TEXT NOT NULL,
+			asset_contract_address TEXT NOT NULL,
+			token_id               TEXT NOT NULL,
+			value_raw              TEXT NOT NULL,
+			value                  TEXT NOT NULL,
+			status                 TEXT,
+			direction              TEXT,
+			UNIQUE (chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw)
+		)`,
+		"CREATE INDEX idx_transfers_hash ON transfers (chain, hash)",
+		`INSERT INTO transfers (chain, hash, transaction_type, from_address, to_address, asset_contract_address, token_id, value_raw, value, status, direction)
+		VALUES ('ethereum', '0xpayout', 'ERC-20 Transfer', '0x2222222222222222222222222222222222222222',
+			'0x1111111111111111111111111111111111111111', '0x5555555555555555555555555555555555555555', '', '1000000', '1', 'Success', 'IN')`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	if err := NewSQLiteExporter(filename, Options{}).Export(payouts()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := transferLogIndexes(t, filename); len(got) != 2 || got[0] != "7" || got[1] != "8" {
+		t.Errorf("log indexes %v, want [7 8]", got)
+	}
+}
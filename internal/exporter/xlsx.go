@@ -0,0 +1,379 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	xlsxSummarySheet  = "Summary"
+	xlsxDateFormat    = "yyyy-mm-dd hh:mm:ss"
+	xlsxAmountFormat  = "#,##0.##################"
+	xlsxMaxSheetName  = 31
+	xlsxInvalidInName = `[]:*?/\`
+)
+
+// xlsxHeader lists the columns of every transaction type sheet
+var xlsxHeader = []string{
+	"Transaction Hash",
+	"Date & Time (UTC)",
+	"From Address",
+	"To Address",
+	"Asset Contract Address",
+	"Asset Symbol",
+	"Asset Name",
+	"Token ID",
+	"Amount",
+	"Gas Fee (ETH)",
+	"Block Number",
+	"Status",
+	"Direction",
+}
+
+// XLSXExporter handles exporting transactions to an Excel workbook with a sheet per
+// transaction type and a summary dashboard
+type XLSXExporter struct {
+	fileNaming
+	filename string
+	options  Options
+}
+
+// xlsxStyles holds the style IDs shared by all sheets
+type xlsxStyles struct {
+	header int
+	date   int
+	amount int
+}
+
+// assetFlow accumulates inflows and outflows of one asset
+type assetFlow struct {
+	symbol   string
+	contract string
+	decimals int
+	inflow   *big.Int
+	outflow  *big.Int
+}
+
+// NewXLSXExporter creates a new Excel workbook exporter
+func NewXLSXExporter(filename string, options Options) *XLSXExporter {
+	return &XLSXExporter{
+		filename: filename,
+		options:  options,
+	}
+}
+
+// Export writes transactions to an XLSX workbook with typed cells, frozen headers and autofilters
+func (e *XLSXExporter) Export(transactions []*models.Transaction) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := e.createStyles(f)
+	if err != nil {
+		return err
+	}
+
+	// The default sheet becomes the summary so it opens first
+	if err := f.SetSheetName(f.GetSheetName(0), xlsxSummarySheet); err != nil {
+		return fmt.Errorf("failed to create summary sheet: %w", err)
+	}
+
+	byType := make(map[models.TransactionType][]*models.Transaction)
+	var types []models.TransactionType
+	for _, tx := range transactions {
+		if _, ok := byType[tx.TransactionType]; !ok {
+			types = append(types, tx.TransactionType)
+		}
+		byType[tx.TransactionType] = append(byType[tx.TransactionType], tx)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, txType := range types {
+		if err := e.writeTypeSheet(f, styles, xlsxSheetName(string(txType)), byType[txType]); err != nil {
+			return err
+		}
+	}
+
+	if err := e.writeSummarySheet(f, styles, transactions, types, byType); err != nil {
+		return err
+	}
+
+	file, err := e.create(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to create XLSX file: %w", err)
+	}
+	if _, err := f.WriteTo(file); err != nil {
+		file.Abort()
+		return fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save XLSX file: %w", err)
+	}
+
+	return nil
+}
+
+// GetExportSummary returns a summary of the export operation
+func (e *XLSXExporter) GetExportSummary(transactions []*models.Transaction) map[string]interface{} {
+	return Summarize(transactions, e.filename)
+}
+
+// createStyles registers the header, date and amount cell styles
+func (e *XLSXExporter) createStyles(f *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+
+	styles.header, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	if err != nil {
+		return styles, fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	dateFormat := xlsxDateFormat
+	styles.date, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return styles, fmt.Errorf("failed to create date style: %w", err)
+	}
+
+	amountFormat := xlsxAmountFormat
+	styles.amount, err = f.NewStyle(&excelize.Style{CustomNumFmt: &amountFormat})
+	if err != nil {
+		return styles, fmt.Errorf("failed to create amount style: %w", err)
+	}
+
+	return styles, nil
+}
+
+// writeTypeSheet streams the transactions of one type into their own sheet
+func (e *XLSXExporter) writeTypeSheet(f *excelize.File, styles xlsxStyles, sheet string, transactions []*models.Transaction) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("failed to create sheet %s: %w", sheet, err)
+	}
+
+	// The filter must be defined before streaming, which writes the rest of the sheet
+	lastCell, _ := excelize.CoordinatesToCellName(len(xlsxHeader), len(transactions)+1)
+	if err := f.AutoFilter(sheet, "A1:"+lastCell, nil); err != nil {
+		return fmt.Errorf("failed to add autofilter: %w", err)
+	}
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return fmt.Errorf("failed to create sheet writer: %w", err)
+	}
+
+	if err := sw.SetPanes(frozenHeaderPanes()); err != nil {
+		return fmt.Errorf("failed to freeze header: %w", err)
+	}
+	if err := sw.SetColWidth(1, 4, 44); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+	if err := sw.SetColWidth(5, len(xlsxHeader), 20); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+
+	if err := sw.SetRow("A1", headerCells(xlsxHeader, styles.header)); err != nil {
+		return fmt.Errorf("failed to write XLSX header: %w", err)
+	}
+
+	for i, tx := range transactions {
+		row := []interface{}{
+			tx.Hash,
+			excelize.Cell{StyleID: styles.date, Value: tx.DateTime.UTC()},
+			tx.FromAddress,
+			tx.ToAddress,
+			tx.AssetContractAddr,
+			tx.AssetSymbol,
+			tx.AssetName,
+			tx.TokenID,
+			excelize.Cell{StyleID: styles.amount, Value: unitsFloat(tx.Value, tx.AssetDecimals)},
+			excelize.Cell{StyleID: styles.amount, Value: unitsFloat(tx.GasFeeWei, processor.NativeDecimals)},
+			parseInt64(tx.BlockNumber),
+			tx.Status,
+			string(tx.Direction),
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := sw.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write XLSX row: %w", err)
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return fmt.Errorf("failed to write sheet %s: %w", sheet, err)
+	}
+
+	return nil
+}
+
+// writeSummarySheet writes counts per type, per-asset flows and total gas
+func (e *XLSXExporter) writeSummarySheet(f *excelize.File, styles xlsxStyles, transactions []*models.Transaction, types []models.TransactionType, byType map[models.TransactionType][]*models.Transaction) error {
+	sheet := xlsxSummarySheet
+	row := 1
+
+	set := func(col int, value interface{}, style int) {
+		cell, _ := excelize.CoordinatesToCellName(col, row)
+		f.SetCellValue(sheet, cell, value)
+		if style != 0 {
+			f.SetCellStyle(sheet, cell, cell, style)
+		}
+	}
+
+	set(1, "Generated At (UTC)", styles.header)
+	set(2, time.Now().UTC(), styles.date)
+	row++
+	set(1, "Total Transactions", styles.header)
+	set(2, len(transactions), 0)
+	row += 2
+
+	set(1, "Transaction Type", styles.header)
+	set(2, "Count", styles.header)
+	row++
+	for _, txType := range types {
+		set(1, string(txType), 0)
+		set(2, len(byType[txType]), 0)
+		row++
+	}
+	row++
+
+	flows, totalGas := e.assetFlows(transactions)
+
+	set(1, "Total Gas (ETH)", styles.header)
+	set(2, unitsFloat(totalGas, processor.NativeDecimals), styles.amount)
+	row += 2
+
+	flowHeader := row
+	for col, title := range []string{"Asset Symbol", "Asset Contract Address", "Inflow", "Outflow", "Net"} {
+		set(col+1, title, styles.header)
+	}
+	row++
+	for _, flow := range flows {
+		net := new(big.Int).Sub(flow.inflow, flow.outflow)
+		set(1, flow.symbol, 0)
+		set(2, flow.contract, 0)
+		set(3, unitsFloat(flow.inflow, flow.decimals), styles.amount)
+		set(4, unitsFloat(flow.outflow, flow.decimals), styles.amount)
+		set(5, unitsFloat(net, flow.decimals), styles.amount)
+		row++
+	}
+
+	if len(flows) > 0 {
+		lastCell, _ := excelize.CoordinatesToCellName(5, row-1)
+		firstCell, _ := excelize.CoordinatesToCellName(1, flowHeader)
+		if err := f.AutoFilter(sheet, firstCell+":"+lastCell, nil); err != nil {
+			return fmt.Errorf("failed to add autofilter: %w", err)
+		}
+	}
+
+	if err := f.SetColWidth(sheet, "A", "B", 44); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+	if err := f.SetColWidth(sheet, "C", "E", 24); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+
+	return nil
+}
+
+// assetFlows totals inflows and outflows per asset by direction, leaving internal
+// moves out, and sums the gas paid by the owned addresses
+func (e *XLSXExporter) assetFlows(transactions []*models.Transaction) ([]*assetFlow, *big.Int) {
+	flows := make(map[string]*assetFlow)
+	var ordered []*assetFlow
+	totalGas := new(big.Int)
+
+	for _, tx := range transactions {
+		paidByOwner := len(e.options.Owned) == 0 || e.options.Owned[strings.ToLower(tx.FromAddress)]
+		if isNormalTransaction(tx) && paidByOwner && tx.GasFeeWei != nil {
+			totalGas.Add(totalGas, tx.GasFeeWei)
+		}
+
+		if tx.Value == nil || tx.Status == "Failed" {
+			continue
+		}
+		if tx.Direction != models.DirectionIn && tx.Direction != models.DirectionOut {
+			continue
+		}
+
+		contract := strings.ToLower(tx.AssetContractAddr)
+		if isNativeTransfer(tx) {
+			contract = ""
+		}
+		key := tx.Chain + "_" + contract
+		flow, ok := flows[key]
+		if !ok {
+			flow = &assetFlow{
+				symbol:   tx.AssetSymbol,
+				contract: contract,
+				decimals: tx.AssetDecimals,
+				inflow:   new(big.Int),
+				outflow:  new(big.Int),
+			}
+			flows[key] = flow
+			ordered = append(ordered, flow)
+		}
+
+		if tx.Direction == models.DirectionIn {
+			flow.inflow.Add(flow.inflow, tx.Value)
+		} else {
+			flow.outflow.Add(flow.outflow, tx.Value)
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].symbol < ordered[j].symbol
+	})
+
+	return ordered, totalGas
+}
+
+// frozenHeaderPanes freezes the first row of a sheet
+func frozenHeaderPanes() *excelize.Panes {
+	return &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}
+}
+
+// headerCells returns styled header cells for a stream writer row
+func headerCells(titles []string, style int) []interface{} {
+	cells := make([]interface{}, len(titles))
+	for i, title := range titles {
+		cells[i] = excelize.Cell{StyleID: style, Value: title}
+	}
+	return cells
+}
+
+// unitsFloat converts a raw amount to a floating point number of whole units.
+// Spreadsheets hold 15 significant digits, so this is as precise as the cell allows.
+func unitsFloat(value *big.Int, decimals int) float64 {
+	amount, err := strconv.ParseFloat(processor.FormatUnits(value, decimals), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// xlsxSheetName makes a transaction type usable as a worksheet name
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(xlsxInvalidInName, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if len(name) > xlsxMaxSheetName {
+		name = name[:xlsxMaxSheetName]
+	}
+	return name
+}
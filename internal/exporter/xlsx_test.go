@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestXLSXWorkbook(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallet.xlsx")
+	options := Options{Owned: map[string]bool{testOwner: true}}
+	if err := NewXLSXExporter(filename, options).Export(sampleTransactions()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// The summary opens first, followed by one sheet per type in name order
+	want := []string{xlsxSummarySheet, string(models.ERC20Transfer), string(models.ETHTransfer)}
+	if got := f.GetSheetList(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("sheets %v, want %v", got, want)
+	}
+
+	filters := make(map[string]string)
+	for _, name := range f.GetDefinedName() {
+		if name.Name == "_xlnm._FilterDatabase" {
+			filters[name.Scope] = name.RefersTo
+		}
+	}
+
+	for _, sheet := range want[1:] {
+		panes, err := f.GetPanes(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !panes.Freeze || panes.YSplit != 1 || panes.TopLeftCell != "A2" {
+			t.Errorf("%s header is not frozen: %+v", sheet, panes)
+		}
+		if got := filters[sheet]; got != "'"+sheet+"'!$A$1:$M$2" {
+			t.Errorf("%s autofilter covers %q", sheet, got)
+		}
+
+		header, err := f.GetCellValue(sheet, "A1")
+		if err != nil || header != xlsxHeader[0] {
+			t.Errorf("%s header starts with %q", sheet, header)
+		}
+	}
+
+	// Dates, amounts and block numbers are numeric cells
+	sheet := string(models.ETHTransfer)
+	cells := map[string]string{
+		"A2": "0xaaa",
+		"B2": "45291.9375", // 2023-12-31 22:30 as a spreadsheet serial date
+		"I2": "1.5",
+		"J2": "2.1e-05",
+		"K2": "18000000",
+	}
+	for cell, want := range cells {
+		got, err := f.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			if a, errA := strconv.ParseFloat(got, 64); errA != nil || a != mustFloat(t, want) {
+				t.Errorf("%s!%s holds %q, want %q", sheet, cell, got, want)
+			}
+		}
+	}
+	formatted, _ := f.GetCellValue(sheet, "B2")
+	if formatted != "2023-12-31 22:30:00" {
+		t.Errorf("date cell shows %q", formatted)
+	}
+
+	// The summary counts types, gas paid and flows per asset
+	rows, err := f.GetRows(xlsxSummarySheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string][]string)
+	for _, row := range rows {
+		if len(row) > 1 {
+			values[row[0]] = row[1:]
+		}
+	}
+	expect := map[string][]string{
+		"Total Transactions":         {"2"},
+		string(models.ERC20Transfer): {"1"},
+		string(models.ETHTransfer):   {"1"},
+		"ETH":                        {"", "1.5", "0", "1.5"},
+		"USDC":                       {testToken, "0", "2.5", "-2.5"},
+	}
+	for key, want := range expect {
+		if got := values[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("summary %s: %v, want %v", key, got, want)
+		}
+	}
+	if got := values["Total Gas (ETH)"]; len(got) != 1 || got[0] != "0" {
+		t.Errorf("gas paid by others counted: %v", got)
+	}
+}
+
+func mustFloat(t *testing.T, s string) float64 {
+	t.Helper()
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	tests := map[string]string{
+		"ERC-20 Transfer":                   "ERC-20 Transfer",
+		"Swap [in/out]":                     "Swap -in-out-",
+		"A very long transaction type name": "A very long transaction type na",
+	}
+	for name, want := range tests {
+		if got := xlsxSheetName(name); got != want {
+			t.Errorf("%q became %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Format selects how log records are written
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// ProgressMode selects how progress is reported
+type ProgressMode string
+
+const (
+	ProgressAuto ProgressMode = "auto" // A bar on a terminal, JSON events otherwise
+	ProgressBar  ProgressMode = "bar"
+	ProgressJSON ProgressMode = "json"
+	ProgressNone ProgressMode = "none"
+)
+
+// Options configures logging and progress reporting
+type Options struct {
+	Format   Format
+	Level    slog.Level
+	Progress ProgressMode
+}
+
+// ParseFormat validates a log format name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatText, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported log format: %s (use text or json)", name)
+	}
+}
+
+// ParseProgressMode validates a progress mode name
+func ParseProgressMode(name string) (ProgressMode, error) {
+	switch mode := ProgressMode(strings.ToLower(name)); mode {
+	case ProgressAuto, ProgressBar, ProgressJSON, ProgressNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported progress mode: %s (use auto, bar, json or none)", name)
+	}
+}
+
+// Setup makes structured logging to stderr the default for the slog package and the
+// standard logger, and installs the progress reporter selected by the options. Standard
+// output is left to the results of commands, so it can be piped.
+func Setup(options Options) {
+	out := &terminal{w: os.Stderr}
+
+	handlerOptions := &slog.HandlerOptions{Level: options.Level}
+	var handler slog.Handler
+	if options.Format == FormatJSON {
+		handler = slog.NewJSONHandler(out, handlerOptions)
+	} else {
+		handler = slog.NewTextHandler(out, handlerOptions)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	mode := options.Progress
+	if mode == ProgressAuto {
+		mode = ProgressJSON
+		if isTerminal(os.Stderr) {
+			mode = ProgressBar
+		}
+	}
+	switch mode {
+	case ProgressBar:
+		SetProgress(&barProgress{out: out, stages: make(map[string]*stage)})
+	case ProgressJSON:
+		SetProgress(&jsonProgress{logger: slog.New(slog.NewJSONHandler(out, nil))})
+	default:
+		SetProgress(nil)
+	}
+}
+
+// isTerminal reports whether a file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminal serializes writes to stderr and keeps a status line, such as a progress bar,
+// at the bottom: it is cleared before every other write and drawn again after it
+type terminal struct {
+	mu     sync.Mutex
+	w      io.Writer
+	status string
+}
+
+// Write writes log output above the status line
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != "" {
+		fmt.Fprint(t.w, "\r\033[K")
+	}
+	n, err := t.w.Write(p)
+	if t.status != "" {
+		fmt.Fprint(t.w, t.status)
+	}
+	return n, err
+}
+
+// setStatus replaces the status line, or removes it when empty
+func (t *terminal) setStatus(status string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprint(t.w, "\r\033[K", status)
+	t.status = status
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// barWidth is the number of cells of a progress bar with a known total
+const barWidth = 30
+
+// Progress reports how far long-running stages have come. Stages are named by the caller,
+// e.g. "0xa39b... token transactions", and may run concurrently.
+type Progress interface {
+	// Update reports that done of total units of a stage are complete; total is 0 when unknown
+	Update(stage string, done, total int)
+	// Finish reports that a stage is complete with done units
+	Finish(stage string, done int)
+}
+
+var (
+	progressMu sync.RWMutex
+	progress   Progress = noProgress{}
+)
+
+// SetProgress replaces the progress reporter; nil discards progress
+func SetProgress(p Progress) {
+	if p == nil {
+		p = noProgress{}
+	}
+	progressMu.Lock()
+	progress = p
+	progressMu.Unlock()
+}
+
+// Update reports the progress of a stage to the current reporter
+func Update(stage string, done, total int) {
+	progressMu.RLock()
+	p := progress
+	progressMu.RUnlock()
+	p.Update(stage, done, total)
+}
+
+// Finish reports the completion of a stage to the current reporter
+func Finish(stage string, done int) {
+	progressMu.RLock()
+	p := progress
+	progressMu.RUnlock()
+	p.Finish(stage, done)
+}
+
+// noProgress discards progress, for quiet and server runs
+type noProgress struct{}
+
+func (noProgress) Update(string, int, int) {}
+func (noProgress) Finish(string, int)      {}
+
+// jsonProgress writes every update as a JSON event, for CI runs and other non-interactive use:
+//
+//	{"time":"...","level":"INFO","msg":"progress","stage":"...","done":3,"total":10,"finished":false}
+type jsonProgress struct {
+	logger *slog.Logger
+}
+
+// Update writes an unfinished progress event
+func (p *jsonProgress) Update(stage string, done, total int) {
+	p.logger.Info("progress", "stage", stage, "done", done, "total", total, "finished", false)
+}
+
+// Finish writes a finished progress event
+func (p *jsonProgress) Finish(stage string, done int) {
+	p.logger.Info("progress", "stage", stage, "done", done, "total", done, "finished", true)
+}
+
+// stage is the last reported state of a stage shown by a bar
+type stage struct {
+	name        string
+	done, total int
+}
+
+// barProgress draws the most recently updated unfinished stage as a status line below
+// the log output, for interactive runs
+type barProgress struct {
+	out    *terminal
+	mu     sync.Mutex
+	stages map[string]*stage
+	order  []string // Unfinished stages, most recently updated last
+}
+
+// Update redraws the bar for the stage
+func (p *barProgress) Update(name string, done, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stages[name]
+	if !ok {
+		s = &stage{name: name}
+		p.stages[name] = s
+	}
+	s.done, s.total = done, total
+	p.moveLast(name)
+	p.out.setStatus(s.render())
+}
+
+// Finish removes the stage and shows the next unfinished one, if any
+func (p *barProgress) Finish(name string, done int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.stages, name)
+	p.remove(name)
+	if len(p.order) == 0 {
+		p.out.setStatus("")
+		return
+	}
+	p.out.setStatus(p.stages[p.order[len(p.order)-1]].render())
+}
+
+// moveLast marks a stage as the most recently updated
+func (p *barProgress) moveLast(name string) {
+	p.remove(name)
+	p.order = append(p.order, name)
+}
+
+// remove drops a stage from the update order
+func (p *barProgress) remove(name string) {
+	for i, other := range p.order {
+		if other == name {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// render formats a stage as one line, with a bar when its total is known
+func (s *stage) render() string {
+	if s.total <= 0 {
+		return fmt.Sprintf("⏳ %s: %d", s.name, s.done)
+	}
+	filled := barWidth * min(s.done, s.total) / s.total
+	return fmt.Sprintf("⏳ %s [%s%s] %d/%d", s.name, strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), s.done, s.total)
+}
@@ -0,0 +1,262 @@
+package manifest
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"crypto-acc-tracking/internal/version"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Suffix is appended to an output file name to name its manifest
+const Suffix = ".manifest.json"
+
+// BlockRange is the lowest and highest block covered by an export on one chain
+type BlockRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// File describes one output file and its content hash
+type File struct {
+	Path   string `json:"path"` // Relative to the manifest
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	Rows   int    `json:"rows"`
+}
+
+// Manifest records what an export contains and how it was produced, so the files can be
+// checked for alteration later. Its content depends only on the export, not on when it ran.
+type Manifest struct {
+	ToolVersion string                         `json:"toolVersion"`
+	Addresses   []string                       `json:"addresses"`
+	Chains      []string                       `json:"chains"`
+	BlockRanges map[string]BlockRange          `json:"blockRanges"`
+	Rows        int                            `json:"rows"`
+	RowCounts   map[models.TransactionType]int `json:"rowCounts"`
+	Files       []File                         `json:"files"`
+	Options     exporter.Options               `json:"options"`
+}
+
+// New creates an empty manifest for an export of the owned addresses
+func New(owned map[string]bool, options exporter.Options) *Manifest {
+	m := &Manifest{
+		ToolVersion: version.Version,
+		Addresses:   []string{},
+		Chains:      []string{},
+		BlockRanges: make(map[string]BlockRange),
+		RowCounts:   make(map[models.TransactionType]int),
+		Files:       []File{},
+		Options:     options,
+	}
+	for address := range owned {
+		m.Addresses = append(m.Addresses, strings.ToLower(address))
+	}
+	sort.Strings(m.Addresses)
+	return m
+}
+
+// Add counts an exported transaction and extends the block range of its chain
+func (m *Manifest) Add(tx *models.Transaction) {
+	m.Rows++
+	m.RowCounts[tx.TransactionType]++
+
+	block, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	if err != nil {
+		return
+	}
+
+	r, ok := m.BlockRanges[tx.Chain]
+	if !ok {
+		m.Chains = append(m.Chains, tx.Chain)
+		sort.Strings(m.Chains)
+		r = BlockRange{From: block, To: block}
+	}
+	if block < r.From {
+		r.From = block
+	}
+	if block > r.To {
+		r.To = block
+	}
+	m.BlockRanges[tx.Chain] = r
+}
+
+// AddFile hashes an output file and records it with its row count
+func (m *Manifest) AddFile(filename string, rows int) error {
+	sum, size, err := hashFile(filename)
+	if err != nil {
+		return err
+	}
+
+	m.Files = append(m.Files, File{
+		Path:   filepath.Base(filename),
+		SHA256: sum,
+		Size:   size,
+		Rows:   rows,
+	})
+	return nil
+}
+
+// PathFor returns the manifest file name of an output file
+func PathFor(outputFile string) string {
+	return outputFile + Suffix
+}
+
+// Write saves the manifest as indented JSON
+func (m *Manifest) Write(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	file, err := output.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Abort()
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+	return nil
+}
+
+// Load reads a manifest file
+func Load(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Check is the outcome of verifying one file against its manifest entry
+type Check struct {
+	File     File
+	Path     string
+	SHA256   string
+	Rows     int
+	Problems []string
+}
+
+// OK reports whether the file matches the manifest
+func (c Check) OK() bool {
+	return len(c.Problems) == 0
+}
+
+// Verify rechecks the content hash, size and, for CSV files, the row count of an output
+// file listed in the manifest stored at manifestFile
+func (m *Manifest) Verify(manifestFile, filename string) (Check, error) {
+	check := Check{Path: filename, Rows: -1}
+
+	var entry *File
+	for i := range m.Files {
+		if m.Files[i].Path == filepath.Base(filename) {
+			entry = &m.Files[i]
+			break
+		}
+	}
+	if entry == nil && len(m.Files) == 1 {
+		// Rotated exports keep their manifest but not their original name
+		entry = &m.Files[0]
+	}
+	if entry == nil {
+		return check, fmt.Errorf("file %s is not listed in manifest %s", filepath.Base(filename), manifestFile)
+	}
+	check.File = *entry
+
+	sum, size, err := hashFile(filename)
+	if err != nil {
+		return check, err
+	}
+	check.SHA256 = sum
+
+	if sum != entry.SHA256 {
+		check.Problems = append(check.Problems, fmt.Sprintf("SHA-256 is %s, manifest has %s", sum, entry.SHA256))
+	}
+	if size != entry.Size {
+		check.Problems = append(check.Problems, fmt.Sprintf("size is %d bytes, manifest has %d", size, entry.Size))
+	}
+
+	if exporter.InferFormat(filename) == exporter.FormatCSV && m.Options.Format == exporter.FormatCSV {
+		rows, err := countCSVRows(filename, m.Options.CSV)
+		if err != nil {
+			check.Problems = append(check.Problems, err.Error())
+		} else {
+			check.Rows = rows
+			if rows != entry.Rows {
+				check.Problems = append(check.Problems, fmt.Sprintf("%d rows, manifest has %d", rows, entry.Rows))
+			}
+		}
+	}
+
+	return check, nil
+}
+
+// hashFile returns the hex SHA-256 and the size of a file
+func hashFile(filename string) (string, int64, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash %s: %w", filename, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// countCSVRows counts the data records of a CSV export, excluding the header
+func countCSVRows(filename string, schema *exporter.CSVSchema) (int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	if schema != nil && schema.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(schema.Delimiter)
+	}
+
+	rows := -1
+	for {
+		_, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		rows++
+	}
+
+	if rows < 0 {
+		return 0, fmt.Errorf("CSV file has no header")
+	}
+	return rows, nil
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Registry holds the metrics of the tracker, apart from the Go runtime metrics that are
+// only served by Handler
+var Registry = prometheus.NewRegistry()
+
+var (
+	// EtherscanRequests counts HTTP requests to the Etherscan API by action and HTTP status,
+	// "error" when no response was received
+	EtherscanRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_etherscan_requests_total",
+		Help: "HTTP requests sent to the Etherscan API, by action and response status.",
+	}, []string{"action", "status"})
+
+	// EtherscanRequestDuration observes the latency of Etherscan requests by action
+	EtherscanRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crypto_tracker_etherscan_request_duration_seconds",
+		Help:    "Latency of Etherscan API requests, by action.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"action"})
+
+	// EtherscanRetries counts attempts repeated after a failed request
+	EtherscanRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_etherscan_retries_total",
+		Help: "Etherscan API requests retried after a failure, by action.",
+	}, []string{"action"})
+
+	// EtherscanRateLimited counts responses reporting that a rate limit was exceeded
+	EtherscanRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_etherscan_rate_limited_total",
+		Help: "Etherscan API responses rejecting a request for exceeding the rate limit, by action.",
+	}, []string{"action"})
+
+	// RateLimiterWait observes how long requests wait for the shared rate limiter
+	RateLimiterWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "crypto_tracker_rate_limiter_wait_seconds",
+		Help:    "Time requests waited for the shared rate limiter before being sent.",
+		Buckets: []float64{0.001, 0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	// EtherscanKeyRequests is the number of requests sent with each API key of a key pool
+	// today (UTC), by key fingerprint, including earlier runs that share the usage file
+	EtherscanKeyRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "crypto_tracker_etherscan_key_requests_today",
+		Help: "Requests sent today (UTC) with each API key of the key pool, by key fingerprint.",
+	}, []string{"key"})
+
+	// EtherscanKeysBenched counts API keys taken out of a key pool for a while, by reason
+	// ("rate_limited" or "invalid")
+	EtherscanKeysBenched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_etherscan_keys_benched_total",
+		Help: "API keys temporarily taken out of the key pool, by reason.",
+	}, []string{"reason"})
+
+	// TransactionsProcessed counts transactions converted from API results by source
+	TransactionsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_transactions_processed_total",
+		Help: "Transactions processed from Etherscan results, by source (normal, internal, token, nft).",
+	}, []string{"source"})
+
+	// ProcessingErrors counts API results that could not be processed and were skipped
+	ProcessingErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_processing_errors_total",
+		Help: "Etherscan results skipped because they could not be processed, by source.",
+	}, []string{"source"})
+
+	// ExportedRows counts transactions written by exporters by format
+	ExportedRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crypto_tracker_exported_rows_total",
+		Help: "Transactions written to exports, by format.",
+	}, []string{"format"})
+
+	// ExportDuration observes the time from opening an export to completing it, by format
+	// and result ("success" or "failure")
+	ExportDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crypto_tracker_export_duration_seconds",
+		Help:    "Time taken to write an export, by format and result.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	}, []string{"format", "result"})
+)
+
+// runtime holds the Go runtime and process metrics served next to the tracker's own
+var runtime = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		EtherscanRequests,
+		EtherscanRequestDuration,
+		EtherscanRetries,
+		EtherscanRateLimited,
+		RateLimiterWait,
+		EtherscanKeyRequests,
+		EtherscanKeysBenched,
+		TransactionsProcessed,
+		ProcessingErrors,
+		ExportedRows,
+		ExportDuration,
+	)
+	runtime.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the tracker and runtime metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{Registry, runtime}, promhttp.HandlerOpts{})
+}
+
+// Write dumps the tracker metrics collected so far in the Prometheus text format, e.g. for
+// the textfile collector of the node exporter at the end of a CLI run
+func Write(w io.Writer) error {
+	families, err := Registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
+	}
+	return nil
+}
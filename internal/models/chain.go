@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chain describes an EVM network supported through the Etherscan family of APIs
+type Chain struct {
+	Name         string `json:"name"`
+	ID           int    `json:"id"`
+	NativeSymbol string `json:"nativeSymbol"`
+	NativeName   string `json:"nativeName"`
+}
+
+// DefaultChain is used when no chain is specified
+var DefaultChain = Chain{Name: "ethereum", ID: 1, NativeSymbol: "ETH", NativeName: "Ethereum"}
+
+// knownChains lists the networks reachable through the Etherscan multichain API
+var knownChains = []Chain{
+	DefaultChain,
+	{Name: "arbitrum", ID: 42161, NativeSymbol: "ETH", NativeName: "Ethereum"},
+	{Name: "optimism", ID: 10, NativeSymbol: "ETH", NativeName: "Ethereum"},
+	{Name: "base", ID: 8453, NativeSymbol: "ETH", NativeName: "Ethereum"},
+	{Name: "polygon", ID: 137, NativeSymbol: "POL", NativeName: "Polygon"},
+	{Name: "bsc", ID: 56, NativeSymbol: "BNB", NativeName: "BNB"},
+	{Name: "avalanche", ID: 43114, NativeSymbol: "AVAX", NativeName: "Avalanche"},
+}
+
+// Chains returns the supported networks
+func Chains() []Chain {
+	return append([]Chain(nil), knownChains...)
+}
+
+// LookupChain returns the chain with the given name, defaulting to Ethereum for an empty name
+func LookupChain(name string) (Chain, error) {
+	if name == "" {
+		return DefaultChain, nil
+	}
+
+	for _, chain := range knownChains {
+		if strings.EqualFold(chain.Name, name) {
+			return chain, nil
+		}
+	}
+
+	return Chain{}, fmt.Errorf("unsupported chain: %s", name)
+}
@@ -17,6 +17,16 @@ const (
 	InternalTx      TransactionType = "Internal Transfer"
 )
 
+// Direction describes how a transaction moves value relative to the tracked wallets
+type Direction string
+
+const (
+	DirectionIn           Direction = "In"
+	DirectionOut          Direction = "Out"
+	DirectionSelf         Direction = "Self"
+	DirectionInternalMove Direction = "Internal Move"
+)
+
 // Transaction represents a unified transaction structure
 type Transaction struct {
 	Hash              string          `json:"hash"`
@@ -27,13 +37,18 @@ type Transaction struct {
 	AssetContractAddr string          `json:"assetContractAddr"`
 	AssetSymbol       string          `json:"assetSymbol"`
 	AssetName         string          `json:"assetName"`
+	AssetDecimals     int             `json:"assetDecimals"`
 	TokenID           string          `json:"tokenId"`
 	Value             *big.Int        `json:"value"`
 	ValueFormatted    string          `json:"valueFormatted"`
 	GasFeeETH         string          `json:"gasFeeEth"`
+	GasFeeWei         *big.Int        `json:"gasFeeWei"`
 	BlockNumber       string          `json:"blockNumber"`
 	TransactionIndex  string          `json:"transactionIndex"`
+	LogIndex          string          `json:"logIndex"` // Log index of token transfers, trace ID of internal transactions
 	Status            string          `json:"status"`
+	Chain             string          `json:"chain"`
+	Direction         Direction       `json:"direction"`
 }
 
 // EtherscanNormalTx represents a normal transaction from Etherscan API
@@ -93,6 +108,7 @@ type EtherscanTokenTx struct {
 	TokenSymbol       string `json:"tokenSymbol"`
 	TokenDecimal      string `json:"tokenDecimal"`
 	TransactionIndex  string `json:"transactionIndex"`
+	LogIndex          string `json:"logIndex"`
 	Gas               string `json:"gas"`
 	GasPrice          string `json:"gasPrice"`
 	GasUsed           string `json:"gasUsed"`
@@ -109,6 +125,7 @@ type EtherscanNFTTx struct {
 	Nonce             string `json:"nonce"`
 	BlockHash         string `json:"blockHash"`
 	TransactionIndex  string `json:"transactionIndex"`
+	LogIndex          string `json:"logIndex"`
 	Gas               string `json:"gas"`
 	GasPrice          string `json:"gasPrice"`
 	GasUsed           string `json:"gasUsed"`
@@ -124,6 +141,30 @@ type EtherscanNFTTx struct {
 	Confirmations     string `json:"confirmations"`
 }
 
+// EtherscanERC1155Tx represents an ERC-1155 multi-token transfer from Etherscan API
+type EtherscanERC1155Tx struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  string `json:"transactionIndex"`
+	LogIndex          string `json:"logIndex"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	GasUsed           string `json:"gasUsed"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	Input             string `json:"input"`
+	ContractAddress   string `json:"contractAddress"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	TokenID           string `json:"tokenID"`
+	TokenValue        string `json:"tokenValue"`
+	TokenName         string `json:"tokenName"`
+	TokenSymbol       string `json:"tokenSymbol"`
+	Confirmations     string `json:"confirmations"`
+}
+
 // EtherscanResponse represents the API response structure
 type EtherscanResponse struct {
 	Status  string      `json:"status"`
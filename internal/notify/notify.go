@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRetries    = 3               // Delivery attempts per sink unless set
+	DefaultRetryDelay = 2 * time.Second // Wait before the first retry, doubled for each further one
+)
+
+// Config holds the notification settings of the configuration file
+type Config struct {
+	Sinks      []SinkConfig  `yaml:"sinks,omitempty"`
+	Rules      []RuleConfig  `yaml:"rules,omitempty"`
+	Retries    int           `yaml:"retries,omitempty"`    // Delivery attempts per sink
+	RetryDelay time.Duration `yaml:"retryDelay,omitempty"` // Wait before the first retry
+	DeadLetter string        `yaml:"deadLetter,omitempty"` // NDJSON file recording notifications that could not be delivered
+}
+
+// Notification is sent when a transaction matches a rule
+type Notification struct {
+	Rule        string              `json:"rule"`
+	Address     string              `json:"address"`
+	Chain       string              `json:"chain"`
+	SentAt      time.Time           `json:"sentAt"`
+	Transaction *models.Transaction `json:"transaction"`
+}
+
+// Text returns a one-line description of the notification for chat messages
+func (n *Notification) Text() string {
+	tx := n.Transaction
+	direction := string(tx.Direction)
+	if direction == "" {
+		direction = string(tx.TransactionType)
+	}
+	text := fmt.Sprintf("[%s] %s %s %s from %s to %s in block %s, tx %s",
+		n.Rule, direction, tx.ValueFormatted, tx.AssetSymbol, tx.FromAddress, tx.ToAddress, tx.BlockNumber, tx.Hash)
+	if tx.Status == "Failed" {
+		text += " (failed)"
+	}
+	return text
+}
+
+// deadLetter is one line of the dead-letter file
+type deadLetter struct {
+	FailedAt     time.Time     `json:"failedAt"`
+	Sink         string        `json:"sink"`
+	Attempts     int           `json:"attempts"`
+	Error        string        `json:"error"`
+	Notification *Notification `json:"notification"`
+}
+
+// Notifier checks transactions against the rules and delivers notifications to the sinks
+type Notifier struct {
+	sinks      map[string]Sink
+	order      []string
+	rules      []*rule
+	retries    int
+	retryDelay time.Duration
+	deadLetter string
+
+	mu     sync.Mutex
+	called map[string]bool // Contracts called so far, for newContract rules
+}
+
+// New creates a notifier from the configuration, validating every sink and rule
+func New(config Config) (*Notifier, error) {
+	n := &Notifier{
+		sinks:      make(map[string]Sink),
+		retries:    config.Retries,
+		retryDelay: config.RetryDelay,
+		deadLetter: config.DeadLetter,
+		called:     make(map[string]bool),
+	}
+	if n.retries <= 0 {
+		n.retries = DefaultRetries
+	}
+	if n.retryDelay <= 0 {
+		n.retryDelay = DefaultRetryDelay
+	}
+
+	for _, sinkConfig := range config.Sinks {
+		sink, err := newSink(sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		if n.sinks[sink.Name()] != nil {
+			return nil, fmt.Errorf("duplicate notification sink %s", sink.Name())
+		}
+		n.sinks[sink.Name()] = sink
+		n.order = append(n.order, sink.Name())
+	}
+
+	for _, ruleConfig := range config.Rules {
+		r, err := compileRule(ruleConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range r.sinks {
+			if n.sinks[name] == nil {
+				return nil, fmt.Errorf("rule %s: unknown sink %s", r.name, name)
+			}
+		}
+		n.rules = append(n.rules, r)
+	}
+
+	if len(n.rules) > 0 && len(n.sinks) == 0 {
+		return nil, fmt.Errorf("notification rules are set but no sinks")
+	}
+	return n, nil
+}
+
+// Enabled reports whether any rule is set
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.rules) > 0
+}
+
+// TracksNewContracts reports whether any rule only matches calls to contracts not called before
+func (n *Notifier) TracksNewContracts() bool {
+	if !n.Enabled() {
+		return false
+	}
+	for _, r := range n.rules {
+		if r.newContract {
+			return true
+		}
+	}
+	return false
+}
+
+// Seen records a transaction from before the notifier started without notifying, so
+// newContract rules do not match contracts the wallet already called
+func (n *Notifier) Seen(tx *models.Transaction) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.remember(tx)
+}
+
+// remember records the contract a transaction calls; the caller holds mu
+func (n *Notifier) remember(tx *models.Transaction) {
+	if tx.TransactionType == models.ContractCall {
+		n.called[strings.ToLower(tx.ToAddress)] = true
+	}
+}
+
+// Check sends a notification for every rule the transaction matches. Deliveries that fail
+// after all retries are written to the dead-letter file; only failing to record them is
+// returned as an error.
+func (n *Notifier) Check(address string, tx *models.Transaction) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	n.mu.Lock()
+	var matched []*rule
+	for _, r := range n.rules {
+		if r.match(tx, n.called) {
+			matched = append(matched, r)
+		}
+	}
+	n.remember(tx)
+	n.mu.Unlock()
+
+	for _, r := range matched {
+		notification := &Notification{
+			Rule:        r.name,
+			Address:     strings.ToLower(address),
+			Chain:       tx.Chain,
+			SentAt:      time.Now().UTC(),
+			Transaction: tx,
+		}
+		sinks := r.sinks
+		if len(sinks) == 0 {
+			sinks = n.order
+		}
+		for _, name := range sinks {
+			if _, err := n.Deliver(n.sinks[name], notification); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sinks returns the sinks in configuration order
+func (n *Notifier) Sinks() []Sink {
+	sinks := make([]Sink, 0, len(n.order))
+	for _, name := range n.order {
+		sinks = append(sinks, n.sinks[name])
+	}
+	return sinks
+}
+
+// Deliver sends a notification to one sink, retrying with exponential backoff, and reports
+// whether it was delivered. A delivery that still fails is reported and written to the
+// dead-letter file; only failing to write it is returned as an error.
+func (n *Notifier) Deliver(sink Sink, notification *Notification) (bool, error) {
+	var err error
+	delay := n.retryDelay
+	for attempt := 1; attempt <= n.retries; attempt++ {
+		if err = sink.Send(notification); err == nil {
+			slog.Info("Notified", "sink", sink.Name(), "rule", notification.Rule, "message", notification.Text())
+			return true, nil
+		}
+		if attempt < n.retries {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	slog.Warn("Failed to notify", "sink", sink.Name(), "attempts", n.retries, "error", err)
+	return false, n.writeDeadLetter(deadLetter{
+		FailedAt:     time.Now().UTC(),
+		Sink:         sink.Name(),
+		Attempts:     n.retries,
+		Error:        err.Error(),
+		Notification: notification,
+	})
+}
+
+// writeDeadLetter appends an undelivered notification to the dead-letter file
+func (n *Notifier) writeDeadLetter(entry deadLetter) error {
+	if n.deadLetter == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.deadLetter, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead-letter file: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RuleConfig selects the transactions a notification is sent for. Every condition that is
+// set must hold; a rule without conditions matches every transaction.
+type RuleConfig struct {
+	Name         string   `yaml:"name"`
+	Direction    string   `yaml:"direction,omitempty"`    // in, out, self or internal
+	Types        []string `yaml:"types,omitempty"`        // Transaction types as accepted by --types
+	Token        string   `yaml:"token,omitempty"`        // Asset contract address
+	MinValue     string   `yaml:"minValue,omitempty"`     // Smallest value, in asset units
+	Status       string   `yaml:"status,omitempty"`       // success or failed
+	Counterparty string   `yaml:"counterparty,omitempty"` // Address on either side of the transfer
+	NewContract  bool     `yaml:"newContract,omitempty"`  // Only contract calls to a contract the wallet never called before
+	Sinks        []string `yaml:"sinks,omitempty"`        // Names of the sinks to notify, empty for all
+}
+
+// directions maps the direction names of rules to transaction directions
+var directions = map[string]models.Direction{
+	"in":       models.DirectionIn,
+	"out":      models.DirectionOut,
+	"self":     models.DirectionSelf,
+	"internal": models.DirectionInternalMove,
+}
+
+// rule is a compiled RuleConfig
+type rule struct {
+	name        string
+	direction   models.Direction
+	filter      processor.Filter
+	newContract bool
+	sinks       []string
+}
+
+// compileRule validates a rule and resolves its conditions
+func compileRule(config RuleConfig) (*rule, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("notification rule without a name")
+	}
+	r := &rule{
+		name:        config.Name,
+		newContract: config.NewContract,
+		sinks:       config.Sinks,
+	}
+
+	if config.Direction != "" {
+		direction, ok := directions[strings.ToLower(config.Direction)]
+		if !ok {
+			return nil, fmt.Errorf("rule %s: unsupported direction %q (supported: in, out, self, internal)", config.Name, config.Direction)
+		}
+		r.direction = direction
+	}
+
+	types, err := processor.ParseTypes(strings.Join(config.Types, ","))
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", config.Name, err)
+	}
+	r.filter.Types = types
+
+	status, err := processor.ParseStatus(config.Status)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", config.Name, err)
+	}
+	r.filter.Status = status
+
+	if config.MinValue != "" {
+		value, ok := new(big.Rat).SetString(config.MinValue)
+		if !ok || value.Sign() < 0 {
+			return nil, fmt.Errorf("rule %s: invalid minimum value: %s", config.Name, config.MinValue)
+		}
+		r.filter.MinValue = value
+	}
+
+	r.filter.Token = strings.ToLower(config.Token)
+	r.filter.Counterparty = strings.ToLower(config.Counterparty)
+	return r, nil
+}
+
+// match reports whether a transaction satisfies the rule. Contracts already called are
+// looked up in called, which the caller keeps up to date.
+func (r *rule) match(tx *models.Transaction, called map[string]bool) bool {
+	if r.direction != "" && tx.Direction != r.direction {
+		return false
+	}
+	if r.newContract && (tx.TransactionType != models.ContractCall || called[strings.ToLower(tx.ToAddress)]) {
+		return false
+	}
+	return r.filter.Match(tx)
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"testing"
+)
+
+// contractCall is a call from the wallet to a contract
+func contractCall(hash, contract string) *models.Transaction {
+	return &models.Transaction{
+		Hash:            hash,
+		FromAddress:     "0x1111111111111111111111111111111111111111",
+		ToAddress:       contract,
+		TransactionType: models.ContractCall,
+		Direction:       models.DirectionOut,
+		Status:          "Success",
+	}
+}
+
+func TestNewContractRule(t *testing.T) {
+	server := newTestServer(t, 0)
+	n, err := New(Config{
+		Sinks: []SinkConfig{{Name: "hook", Type: SinkWebhook, URL: server.URL}},
+		Rules: []RuleConfig{{Name: "new-contract", NewContract: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.TracksNewContracts() {
+		t.Fatal("TracksNewContracts() = false with a newContract rule")
+	}
+
+	// A contract called before the watch started is not new; case does not matter
+	n.Seen(contractCall("0x1", "0xAAAA000000000000000000000000000000000000"))
+	// Other transactions do not mark their recipient as called
+	n.Seen(&models.Transaction{Hash: "0x2", ToAddress: "0xbbbb000000000000000000000000000000000000", TransactionType: models.ETHTransfer})
+
+	for _, tx := range []*models.Transaction{
+		contractCall("0x3", "0xaaaa000000000000000000000000000000000000"),
+		contractCall("0x4", "0xbbbb000000000000000000000000000000000000"),
+		contractCall("0x5", "0xbbbb000000000000000000000000000000000000"),
+	} {
+		if err := n.Check("0x1111111111111111111111111111111111111111", tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("got %d notifications, want 1 for the first call to 0xbbbb", len(requests))
+	}
+	var decoded Notification
+	if err := json.Unmarshal(requests[0].body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Transaction == nil || decoded.Transaction.Hash != "0x4" {
+		t.Errorf("notified for %s, want 0x4", requests[0].body)
+	}
+}
+
+func TestTracksNewContracts(t *testing.T) {
+	sinks := []SinkConfig{{Name: "hook", Type: SinkWebhook, URL: "http://localhost"}}
+	for _, tc := range []struct {
+		name  string
+		rules []RuleConfig
+		want  bool
+	}{
+		{"no rules", nil, false},
+		{"other rules", []RuleConfig{{Name: "failed", Status: "failed"}}, false},
+		{"newContract rule", []RuleConfig{{Name: "failed", Status: "failed"}, {Name: "new", NewContract: true}}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := New(Config{Sinks: sinks, Rules: tc.rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := n.TracksNewContracts(); got != tc.want {
+				t.Errorf("TracksNewContracts() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
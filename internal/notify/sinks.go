@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto-acc-tracking/internal/version"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sink types
+const (
+	SinkWebhook = "webhook"
+	SinkSlack   = "slack"
+	SinkCommand = "command"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of webhook requests
+	SignatureHeader = "X-Crypto-Tracker-Signature"
+	// TimestampHeader carries the Unix time a webhook request was signed at
+	TimestampHeader = "X-Crypto-Tracker-Timestamp"
+
+	// SinkTimeout limits a single delivery attempt
+	SinkTimeout = 30 * time.Second
+)
+
+// SinkConfig describes where notifications are delivered
+type SinkConfig struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`              // webhook, slack or command
+	URL     string            `yaml:"url,omitempty"`     // Endpoint of webhook and slack sinks
+	Secret  string            `yaml:"secret,omitempty"`  // HMAC key signing webhook requests
+	Headers map[string]string `yaml:"headers,omitempty"` // Extra headers of webhook requests
+	Command []string          `yaml:"command,omitempty"` // Program and arguments of command sinks
+}
+
+// Sink delivers notifications to one destination
+type Sink interface {
+	Name() string
+	Send(n *Notification) error
+}
+
+// newSink creates the sink described by a configuration
+func newSink(config SinkConfig) (Sink, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("notification sink without a name")
+	}
+
+	switch config.Type {
+	case SinkWebhook, SinkSlack:
+		if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+			return nil, fmt.Errorf("sink %s: url must be an http or https URL", config.Name)
+		}
+		return &webhookSink{
+			config:     config,
+			httpClient: &http.Client{Timeout: SinkTimeout},
+		}, nil
+	case SinkCommand:
+		if len(config.Command) == 0 {
+			return nil, fmt.Errorf("sink %s: command is required", config.Name)
+		}
+		return &commandSink{config: config}, nil
+	default:
+		return nil, fmt.Errorf("sink %s: unsupported type %q (supported: webhook, slack, command)", config.Name, config.Type)
+	}
+}
+
+// webhookSink posts notifications as JSON, either the full notification signed with the
+// sink secret or, for Slack-compatible endpoints, a message text
+type webhookSink struct {
+	config     SinkConfig
+	httpClient *http.Client
+}
+
+// Name returns the configured sink name
+func (s *webhookSink) Name() string {
+	return s.config.Name
+}
+
+// Send posts one notification and fails unless the endpoint answers with a 2xx status
+func (s *webhookSink) Send(n *Notification) error {
+	var payload interface{} = n
+	if s.config.Type == SinkSlack {
+		payload = map[string]string{"text": n.Text()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "crypto-tracker/"+version.Version)
+	for name, value := range s.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if s.config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(s.config.Secret, timestamp, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// Sign returns the signature of a webhook body: "sha256=" and the hex HMAC-SHA256 of the
+// timestamp, a dot and the body. Receivers recompute it to authenticate the request and
+// reject old timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// envPrefix starts the names of the settings of the tool, including its API keys, and of
+// the variables describing a notification
+const envPrefix = "CRYPTO_TRACKER_"
+
+// commandSink runs a local program for every notification, passing the notification as
+// JSON on standard input and its main fields in CRYPTO_TRACKER_* environment variables
+type commandSink struct {
+	config SinkConfig
+}
+
+// Name returns the configured sink name
+func (s *commandSink) Name() string {
+	return s.config.Name
+}
+
+// Send runs the command and fails if it exits with a non-zero status
+func (s *commandSink) Send(n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SinkTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.config.Command[0], s.config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(inheritedEnv(),
+		"CRYPTO_TRACKER_RULE="+n.Rule,
+		"CRYPTO_TRACKER_ADDRESS="+n.Address,
+		"CRYPTO_TRACKER_CHAIN="+n.Chain,
+		"CRYPTO_TRACKER_TX_HASH="+n.Transaction.Hash,
+		"CRYPTO_TRACKER_MESSAGE="+n.Text(),
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("command failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
+
+// inheritedEnv returns the environment passed on to commands, without the settings of the
+// tool, so API keys and other secrets never reach a notification command
+func inheritedEnv() []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, envPrefix) {
+			env = append(env, variable)
+		}
+	}
+	return env
+}
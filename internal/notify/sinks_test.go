@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"bufio"
+	"crypto-acc-tracking/internal/models"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// request is one request received by a test server
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// testServer records the requests it receives and answers the first failures with a 500
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+	failures int
+}
+
+func newTestServer(t *testing.T, failures int) *testServer {
+	s := &testServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, request{header: r.Header.Clone(), body: body})
+		if len(s.requests) <= s.failures {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) received() []request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]request(nil), s.requests...)
+}
+
+func testNotification() *Notification {
+	return &Notification{
+		Rule:    "large",
+		Address: "0x1111111111111111111111111111111111111111",
+		Chain:   "ethereum",
+		SentAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Transaction: &models.Transaction{
+			Hash:            "0xabc",
+			BlockNumber:     "100",
+			FromAddress:     "0x2222222222222222222222222222222222222222",
+			ToAddress:       "0x1111111111111111111111111111111111111111",
+			ValueFormatted:  "1.5",
+			AssetSymbol:     "ETH",
+			TransactionType: models.ETHTransfer,
+			Direction:       models.DirectionIn,
+			Status:          "Success",
+		},
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	server := newTestServer(t, 0)
+	sink, err := newSink(SinkConfig{Name: "hook", Type: SinkWebhook, URL: server.URL, Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Send(testNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	timestamp := req.header.Get(TimestampHeader)
+	if timestamp == "" {
+		t.Fatal("timestamp header missing")
+	}
+	if got, want := req.header.Get(SignatureHeader), Sign("s3cret", timestamp, req.body); got != want {
+		t.Errorf("signature %q, want %q", got, want)
+	}
+	if Sign("other", timestamp, req.body) == req.header.Get(SignatureHeader) {
+		t.Error("signature does not depend on the secret")
+	}
+
+	var decoded Notification
+	if err := json.Unmarshal(req.body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Rule != "large" || decoded.Transaction == nil || decoded.Transaction.Hash != "0xabc" {
+		t.Errorf("body is not the notification: %s", req.body)
+	}
+}
+
+func TestWebhookWithoutSecretIsUnsigned(t *testing.T) {
+	server := newTestServer(t, 0)
+	sink, err := newSink(SinkConfig{Name: "hook", Type: SinkWebhook, URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Send(testNotification()); err != nil {
+		t.Fatal(err)
+	}
+	if header := server.received()[0].header; header.Get(SignatureHeader) != "" || header.Get(TimestampHeader) != "" {
+		t.Error("unsigned webhook carries signature headers")
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	server := newTestServer(t, 0)
+	sink, err := newSink(SinkConfig{Name: "chat", Type: SinkSlack, URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := testNotification()
+	if err := sink.Send(n); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(server.received()[0].body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != 1 {
+		t.Errorf("payload has fields %v, want only text", payload)
+	}
+	if payload["text"] != n.Text() {
+		t.Errorf("text %q, want %q", payload["text"], n.Text())
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	server := newTestServer(t, 2)
+	n, err := New(Config{
+		Sinks:      []SinkConfig{{Name: "hook", Type: SinkWebhook, URL: server.URL}},
+		Retries:    3,
+		RetryDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered, err := n.Deliver(n.Sinks()[0], testNotification())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !delivered {
+		t.Error("not delivered on the last attempt")
+	}
+	if got := len(server.received()); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestDeliverWritesDeadLetter(t *testing.T) {
+	server := newTestServer(t, 100)
+	deadLetterFile := filepath.Join(t.TempDir(), "dead.ndjson")
+	n, err := New(Config{
+		Sinks:      []SinkConfig{{Name: "hook", Type: SinkWebhook, URL: server.URL}},
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+		DeadLetter: deadLetterFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered, err := n.Deliver(n.Sinks()[0], testNotification())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered {
+		t.Error("reported as delivered")
+	}
+	if got := len(server.received()); got != 2 {
+		t.Errorf("got %d attempts, want 2", got)
+	}
+
+	file, err := os.Open(deadLetterFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var entries []deadLetter
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry deadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Sink != "hook" || entry.Attempts != 2 || !strings.Contains(entry.Error, "500") {
+		t.Errorf("unexpected dead letter %+v", entry)
+	}
+	if entry.Notification == nil || entry.Notification.Transaction.Hash != "0xabc" {
+		t.Errorf("dead letter lacks the notification: %+v", entry)
+	}
+}
+
+func TestCommandEnvironment(t *testing.T) {
+	t.Setenv("CRYPTO_TRACKER_API_KEY", "secret-key")
+	t.Setenv("CRYPTO_TRACKER_API_KEY_POLYGON", "secret-polygon-key")
+	t.Setenv("NOTIFY_TEST_KEPT", "kept")
+
+	envFile := filepath.Join(t.TempDir(), "env")
+	sink, err := newSink(SinkConfig{Name: "cmd", Type: SinkCommand, Command: []string{"sh", "-c", "env > " + envFile}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(testNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := string(data)
+	if strings.Contains(env, "secret-") {
+		t.Errorf("API keys were passed to the command:\n%s", env)
+	}
+	for _, want := range []string{"CRYPTO_TRACKER_RULE=large", "CRYPTO_TRACKER_TX_HASH=0xabc", "NOTIFY_TEST_KEPT=kept"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("environment lacks %s", want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is inserted before the extension of timestamped output names
+const TimestampLayout = "20060102T150405Z"
+
+// File is an output file that is written to a temporary file in the target directory and
+// renamed over the target only once complete, so a failed or interrupted run never
+// replaces a good export with a truncated one
+type File struct {
+	*os.File
+	target string
+	naming Naming
+	done   bool
+}
+
+// Create starts writing filename atomically, replacing any existing file on Commit
+func Create(filename string) (*File, error) {
+	return Naming{}.Create(filename)
+}
+
+// Create starts writing filename atomically under the naming rules, which are applied on
+// Commit: an existing file makes it fail with NoClobber, and previous versions are only
+// rotated once the new file is complete
+func (n Naming) Create(filename string) (*File, error) {
+	temp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := temp.Chmod(0644); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return nil, err
+	}
+
+	return &File{
+		File:   temp,
+		target: filename,
+		naming: n,
+	}, nil
+}
+
+// Commit flushes the file to disk and atomically moves it to the target name
+func (f *File) Commit() error {
+	if f.done {
+		return nil
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Abort()
+		return fmt.Errorf("failed to sync %s: %w", f.target, err)
+	}
+	if err := f.File.Close(); err != nil {
+		f.Abort()
+		return fmt.Errorf("failed to close %s: %w", f.target, err)
+	}
+	if err := f.install(); err != nil {
+		f.Abort()
+		return err
+	}
+	f.done = true
+
+	// Persist the rename itself; not every platform can sync a directory
+	if dir, err := os.Open(filepath.Dir(f.target)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
+
+// install moves the complete temporary file to the target name. With NoClobber it is
+// linked there, which fails if the target exists, instead of renamed over it; with rotation
+// the previous versions are shifted up right before the rename.
+func (f *File) install() error {
+	if f.naming.NoClobber {
+		if err := os.Link(f.Name(), f.target); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return fmt.Errorf("output file %s already exists, refusing to overwrite it", f.target)
+			}
+			return fmt.Errorf("failed to move %s into place: %w", f.target, err)
+		}
+		os.Remove(f.Name())
+		return nil
+	}
+
+	if f.naming.Rotate > 0 {
+		if err := Rotate(f.target, f.naming.Rotate, f.naming.Companions...); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(f.Name(), f.target); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", f.target, err)
+	}
+	return nil
+}
+
+// Abort discards the temporary file and leaves the target untouched
+func (f *File) Abort() {
+	if f.done {
+		return
+	}
+	f.done = true
+	f.File.Close()
+	os.Remove(f.Name())
+}
+
+// Naming controls how output file names are chosen, for scheduled runs
+type Naming struct {
+	NoClobber  bool     // Refuse to replace an existing file
+	Timestamp  bool     // Insert the UTC run time before the extension
+	Rotate     int      // Keep this many previous versions as name.1.ext, name.2.ext, ...
+	Companions []string // Suffixes of files rotated along with the output, such as its manifest
+}
+
+// Resolve returns the file name a run writes to. With NoClobber it fails early when the
+// file already exists, so a run does not fetch only to be refused; Commit of a file created
+// with the naming rules is what guarantees it.
+func (n Naming) Resolve(filename string, now time.Time) (string, error) {
+	if n.Timestamp {
+		filename = Timestamped(filename, now)
+	}
+
+	if n.NoClobber {
+		if _, err := os.Stat(filename); err == nil {
+			return "", fmt.Errorf("output file %s already exists, refusing to overwrite it", filename)
+		}
+	}
+
+	return filename, nil
+}
+
+// Timestamped inserts a UTC timestamp before the extension of a file name
+func Timestamped(filename string, t time.Time) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + t.UTC().Format(TimestampLayout) + ext
+}
+
+// Version returns the name of the n-th previous version of a file, name.n.ext
+func Version(filename string, n int) string {
+	if n == 0 {
+		return filename
+	}
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + strconv.Itoa(n) + ext
+}
+
+// Rotate shifts the existing versions of a file and its companions up by one, dropping
+// the oldest beyond keep, so the name is free for a new export
+func Rotate(filename string, keep int, suffixes ...string) error {
+	suffixes = append([]string{""}, suffixes...)
+	for _, suffix := range suffixes {
+		oldest := Version(filename, keep) + suffix
+		if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", oldest, err)
+		}
+	}
+
+	for n := keep - 1; n >= 0; n-- {
+		for _, suffix := range suffixes {
+			from := Version(filename, n) + suffix
+			if _, err := os.Stat(from); err != nil {
+				continue
+			}
+			if err := os.Rename(from, Version(filename, n+1)+suffix); err != nil {
+				return fmt.Errorf("failed to rotate %s: %w", from, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// write creates filename under the naming rules with content and commits it
+func write(t *testing.T, naming Naming, filename, content string) error {
+	t.Helper()
+	file, err := naming.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return file.Commit()
+}
+
+// read returns the content of a file, or "" when it does not exist
+func read(t *testing.T, filename string) string {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotateOnCommit(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "wallet.csv")
+	naming := Naming{Rotate: 2, Companions: []string{".manifest.json"}}
+
+	for _, content := range []string{"first", "second", "third"} {
+		if err := write(t, naming, target, content); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target+".manifest.json", []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{
+		"wallet.csv":                 "third",
+		"wallet.1.csv":               "second",
+		"wallet.2.csv":               "first",
+		"wallet.1.csv.manifest.json": "second",
+		"wallet.2.csv.manifest.json": "first",
+	}
+	for name, content := range want {
+		if got := read(t, filepath.Join(dir, name)); got != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestAbortKeepsPreviousExport(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "wallet.csv")
+	naming := Naming{Rotate: 3}
+
+	if err := write(t, naming, target, "good"); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := naming.Create(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("trunc")
+	file.Abort()
+
+	if got := read(t, target); got != "good" {
+		t.Errorf("target = %q after a failed run, want the previous export", got)
+	}
+	if got := read(t, filepath.Join(dir, "wallet.1.csv")); got != "" {
+		t.Errorf("a failed run rotated the previous export to wallet.1.csv")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want the temporary file removed", len(entries))
+	}
+}
+
+func TestNoClobberOnCommit(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "wallet.csv")
+	naming := Naming{NoClobber: true}
+
+	// Another run creates the file after this one resolved the name
+	file, err := naming.Create(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("mine")
+
+	err = file.Commit()
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Commit() error = %v, want already exists", err)
+	}
+	if got := read(t, target); got != "other" {
+		t.Errorf("target = %q, want the other run's file untouched", got)
+	}
+
+	if _, err := naming.Resolve(target, time.Now()); err == nil {
+		t.Error("Resolve() accepted an existing file")
+	}
+}
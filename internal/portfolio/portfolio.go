@@ -0,0 +1,236 @@
+package portfolio
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/sorter"
+	"crypto-acc-tracking/internal/tracker"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Wallet represents a single owned address on a chain
+type Wallet struct {
+	Address string `json:"address"`
+	Chain   string `json:"chain"`
+	Label   string `json:"label"`
+}
+
+// Portfolio represents a named set of wallets tracked as one entity
+type Portfolio struct {
+	Name    string   `json:"name"`
+	Wallets []Wallet `json:"wallets"`
+
+	archive *archive.Archive
+	replay  bool
+	filter  processor.Filter
+	apiKeys map[string]string
+	limiter *etherscan.RateLimiter
+	pools   map[string]*etherscan.KeyPool
+}
+
+// Load reads a portfolio definition from a JSON file
+func Load(filename string) (*Portfolio, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read portfolio file: %w", err)
+	}
+
+	var p Portfolio
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse portfolio file: %w", err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Validate checks that the portfolio has at least one valid wallet on a supported chain
+func (p *Portfolio) Validate() error {
+	if len(p.Wallets) == 0 {
+		return fmt.Errorf("portfolio %q has no wallets", p.Name)
+	}
+
+	proc := processor.New()
+	for _, w := range p.Wallets {
+		if !proc.ValidateEthereumAddress(w.Address) {
+			return fmt.Errorf("invalid Ethereum address in portfolio: %s", w.Address)
+		}
+		if _, err := models.LookupChain(w.Chain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Owned returns the set of lowercase addresses belonging to the portfolio
+func (p *Portfolio) Owned() map[string]bool {
+	owned := make(map[string]bool)
+	for _, w := range p.Wallets {
+		owned[strings.ToLower(w.Address)] = true
+	}
+	return owned
+}
+
+// SetAPIKeys sets the API keys of individual chains, by chain name. Wallets on other
+// chains use the key passed to Track, Fetch or Sync.
+func (p *Portfolio) SetAPIKeys(keys map[string]string) {
+	p.apiKeys = keys
+}
+
+// SetRateLimiter paces the API calls of all wallets with one shared limiter
+func (p *Portfolio) SetRateLimiter(limiter *etherscan.RateLimiter) {
+	p.limiter = limiter
+}
+
+// SetKeyPools sets the key pools of individual chains, by chain name, which take the place
+// of their API keys
+func (p *Portfolio) SetKeyPools(pools map[string]*etherscan.KeyPool) {
+	p.pools = pools
+}
+
+// SetFilter restricts the transactions fetched for every wallet
+func (p *Portfolio) SetFilter(filter processor.Filter) {
+	p.filter = filter
+}
+
+// ArchiveTo saves every raw Etherscan response of the wallets to the archive
+func (p *Portfolio) ArchiveTo(a *archive.Archive) {
+	p.archive = a
+	p.replay = false
+}
+
+// ReplayFrom rebuilds the wallet histories from archived responses without network calls
+func (p *Portfolio) ReplayFrom(a *archive.Archive) {
+	p.archive = a
+	p.replay = true
+}
+
+// Fetch runs the tracker for every wallet and merges the results into one history
+// where transfers between owned wallets appear once, marked as internal moves
+func (p *Portfolio) Fetch(apiKey string) ([]*models.Transaction, error) {
+	var allTransactions []*models.Transaction
+	err := p.stream(apiKey, func(tx *models.Transaction) error {
+		allTransactions = append(allTransactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proc := processor.New()
+	allTransactions = proc.DeduplicateTransfers(allTransactions)
+	proc.AssignDirections(allTransactions, p.Owned())
+	proc.SortTransactions(allTransactions, processor.Descending)
+
+	return allTransactions, nil
+}
+
+// Sync fetches the history of every wallet without exporting it, for portfolios that
+// archive their responses, and returns the number of transactions fetched
+func (p *Portfolio) Sync(apiKey string) (int, error) {
+	count := 0
+	err := p.stream(apiKey, func(*models.Transaction) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// stream runs the tracker for every wallet, emitting transactions as their pages arrive
+func (p *Portfolio) stream(apiKey string, emit func(*models.Transaction) error) error {
+	trackers := make(map[string]*tracker.Tracker)
+
+	for _, w := range p.Wallets {
+		chain, err := models.LookupChain(w.Chain)
+		if err != nil {
+			return err
+		}
+
+		t, ok := trackers[chain.Name]
+		if !ok {
+			key := apiKey
+			if chainKey := p.apiKeys[chain.Name]; chainKey != "" {
+				key = chainKey
+			}
+
+			t = tracker.NewForChain(key, chain)
+			t.SetFilter(p.filter)
+			if p.limiter != nil {
+				t.SetRateLimiter(p.limiter)
+			}
+			if pool := p.pools[chain.Name]; pool != nil {
+				t.SetKeyPool(pool)
+			}
+			if p.replay {
+				t.ReplayFrom(p.archive)
+			} else if p.archive != nil {
+				t.ArchiveTo(p.archive)
+			}
+			trackers[chain.Name] = t
+		}
+
+		slog.Info("Tracking wallet", "address", strings.ToLower(w.Address), "label", w.Label, "chain", chain.Name)
+		if err := t.StreamTransactions(w.Address, emit); err != nil {
+			return fmt.Errorf("failed to track wallet %s: %w", w.Address, err)
+		}
+	}
+
+	return nil
+}
+
+// Labels returns the wallet labels keyed by lowercase address
+func (p *Portfolio) Labels() map[string]string {
+	labels := make(map[string]string)
+	for _, w := range p.Wallets {
+		if w.Label != "" {
+			labels[strings.ToLower(w.Address)] = w.Label
+		}
+	}
+	return labels
+}
+
+// Track fetches the whole portfolio and exports it as a single file, sorting on disk so
+// memory use stays bounded regardless of the size of the wallets
+func (p *Portfolio) Track(apiKey, outputFile string, options exporter.Options) error {
+	slog.Info("Tracking portfolio", "portfolio", p.Name, "wallets", len(p.Wallets))
+
+	s, err := sorter.New("", sorter.DefaultChunkSize, options.Order.Less)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := p.stream(apiKey, s.Add); err != nil {
+		return err
+	}
+
+	labels := p.Labels()
+	for address, label := range options.Labels {
+		labels[address] = label
+	}
+	options.Labels = labels
+
+	options.Filter = p.filter.Spec()
+	summary, err := tracker.ExportSorted(s, p.Owned(), outputFile, options)
+	if err != nil {
+		return err
+	}
+
+	summary["portfolio"] = p.Name
+	summary["wallets"] = len(p.Wallets)
+	tracker.LogSummary(summary)
+
+	slog.Info("Export completed successfully")
+	return nil
+}
@@ -0,0 +1,308 @@
+package processor
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Status filter values
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// typeNames maps the short names accepted by --types to transaction types
+var typeNames = map[string]models.TransactionType{
+	"eth":      models.ETHTransfer,
+	"native":   models.ETHTransfer,
+	"erc20":    models.ERC20Transfer,
+	"erc721":   models.ERC721Transfer,
+	"nft":      models.ERC721Transfer,
+	"erc1155":  models.ERC1155Transfer,
+	"contract": models.ContractCall,
+	"internal": models.InternalTx,
+}
+
+// Filter selects which transactions are tracked. Block ranges and token contracts can be
+// applied by the Etherscan API at fetch time; everything else is checked by Match.
+type Filter struct {
+	StartBlock   int                             // First block to include, 0 for genesis
+	EndBlock     int                             // Last block to include, 0 for the latest block
+	FromTime     time.Time                       // Earliest time to include, zero for no lower bound
+	ToTime       time.Time                       // Time from which transactions are excluded, zero for no upper bound
+	Types        map[models.TransactionType]bool // Transaction types to include, empty for all
+	Token        string                          // Lowercase asset contract address to include
+	MinValue     *big.Rat                        // Smallest value to include, in asset units
+	Status       string                          // StatusSuccess or StatusFailed, empty for both
+	Counterparty string                          // Lowercase address on either side of the transfer
+}
+
+// typeOrder lists the short names Spec writes transaction types with, in output order
+var typeOrder = []string{"eth", "contract", "internal", "erc20", "erc721", "erc1155"}
+
+// FilterSpec holds the settings of a filter as given by the user, e.g. as command line
+// flags or query parameters. Empty settings do not restrict the filter.
+type FilterSpec struct {
+	FromDate     string `json:"fromDate,omitempty"` // YYYY-MM-DD or RFC 3339; a plain date is the start of the day
+	ToDate       string `json:"toDate,omitempty"`   // YYYY-MM-DD or RFC 3339; a plain date includes the whole day
+	StartBlock   int    `json:"startBlock,omitempty"`
+	EndBlock     int    `json:"endBlock,omitempty"`
+	Types        string `json:"types,omitempty"` // Comma-separated transaction types as accepted by ParseTypes
+	Token        string `json:"token,omitempty"`
+	MinValue     string `json:"minValue,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Counterparty string `json:"counterparty,omitempty"`
+}
+
+// ParseFilter validates the settings of a filter and resolves them into a Filter
+func ParseFilter(spec FilterSpec) (Filter, error) {
+	filter := Filter{
+		StartBlock: spec.StartBlock,
+		EndBlock:   spec.EndBlock,
+	}
+	if spec.StartBlock < 0 || spec.EndBlock < 0 {
+		return filter, fmt.Errorf("block numbers must not be negative")
+	}
+	if spec.EndBlock > 0 && spec.StartBlock > spec.EndBlock {
+		return filter, fmt.Errorf("start block %d is after end block %d", spec.StartBlock, spec.EndBlock)
+	}
+
+	if spec.FromDate != "" {
+		from, err := ParseDate(spec.FromDate)
+		if err != nil {
+			return filter, err
+		}
+		filter.FromTime = from
+	}
+	if spec.ToDate != "" {
+		to, err := ParseDate(spec.ToDate)
+		if err != nil {
+			return filter, err
+		}
+		// A plain date includes the whole day
+		if !strings.Contains(spec.ToDate, "T") {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.ToTime = to
+	}
+	if !filter.FromTime.IsZero() && !filter.ToTime.IsZero() && !filter.FromTime.Before(filter.ToTime) {
+		return filter, fmt.Errorf("from date must be before to date")
+	}
+
+	types, err := ParseTypes(spec.Types)
+	if err != nil {
+		return filter, err
+	}
+	filter.Types = types
+
+	proc := New()
+	if spec.Token != "" {
+		if !proc.ValidateEthereumAddress(spec.Token) {
+			return filter, fmt.Errorf("invalid token contract address: %s", spec.Token)
+		}
+		filter.Token = strings.ToLower(spec.Token)
+	}
+	if spec.Counterparty != "" {
+		if !proc.ValidateEthereumAddress(spec.Counterparty) {
+			return filter, fmt.Errorf("invalid counterparty address: %s", spec.Counterparty)
+		}
+		filter.Counterparty = strings.ToLower(spec.Counterparty)
+	}
+
+	if spec.MinValue != "" {
+		value, ok := new(big.Rat).SetString(spec.MinValue)
+		if !ok || value.Sign() < 0 {
+			return filter, fmt.Errorf("invalid minimum value: %s", spec.MinValue)
+		}
+		filter.MinValue = value
+	}
+
+	status, err := ParseStatus(spec.Status)
+	if err != nil {
+		return filter, err
+	}
+	filter.Status = status
+
+	return filter, nil
+}
+
+// ParseTypes resolves a comma-separated list of transaction types, given either by
+// short name (eth, erc20, erc721, erc1155, contract, internal) or by full name
+func ParseTypes(list string) (map[models.TransactionType]bool, error) {
+	types := make(map[models.TransactionType]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		txType, ok := typeNames[strings.ToLower(strings.ReplaceAll(name, "-", ""))]
+		if !ok {
+			for _, known := range typeNames {
+				if strings.EqualFold(name, string(known)) {
+					txType, ok = known, true
+				}
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("unsupported transaction type %q (supported: eth, erc20, erc721, erc1155, contract, internal)", name)
+		}
+		types[txType] = true
+	}
+	return types, nil
+}
+
+// ParseStatus resolves a --status value
+func ParseStatus(name string) (string, error) {
+	switch status := strings.ToLower(strings.TrimSpace(name)); status {
+	case "", StatusSuccess, StatusFailed:
+		return status, nil
+	}
+	return "", fmt.Errorf("unsupported status %q (supported: success, failed)", name)
+}
+
+// ParseDate parses a YYYY-MM-DD date, taken as midnight UTC, or an RFC 3339 time
+func ParseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD or RFC 3339)", value)
+	}
+	return t, nil
+}
+
+// Spec returns settings that ParseFilter resolves into the same filter, for recording
+// which filter an export was made with. Times are written in RFC 3339, so the end of a
+// plain to-date shows as the midnight after it.
+func (f Filter) Spec() FilterSpec {
+	spec := FilterSpec{
+		StartBlock:   f.StartBlock,
+		EndBlock:     f.EndBlock,
+		Token:        f.Token,
+		Status:       f.Status,
+		Counterparty: f.Counterparty,
+	}
+	if !f.FromTime.IsZero() {
+		spec.FromDate = f.FromTime.UTC().Format(time.RFC3339)
+	}
+	if !f.ToTime.IsZero() {
+		spec.ToDate = f.ToTime.UTC().Format(time.RFC3339)
+	}
+
+	var types []string
+	for _, name := range typeOrder {
+		if f.Types[typeNames[name]] {
+			types = append(types, name)
+		}
+	}
+	spec.Types = strings.Join(types, ",")
+
+	if f.MinValue != nil {
+		if f.MinValue.IsInt() {
+			spec.MinValue = f.MinValue.RatString()
+		} else {
+			spec.MinValue = strings.TrimRight(f.MinValue.FloatString(36), "0")
+		}
+	}
+	return spec
+}
+
+// IsEmpty reports whether the filter lets every transaction through
+func (f Filter) IsEmpty() bool {
+	return f.StartBlock == 0 && f.EndBlock == 0 && f.FromTime.IsZero() && f.ToTime.IsZero() &&
+		len(f.Types) == 0 && f.Token == "" && f.MinValue == nil && f.Status == "" && f.Counterparty == ""
+}
+
+// Wants reports whether transactions of any of the given types can pass the filter, so
+// streams that cannot produce a wanted transaction need not be fetched at all
+func (f Filter) Wants(types ...models.TransactionType) bool {
+	for _, txType := range types {
+		if len(f.Types) > 0 && !f.Types[txType] {
+			continue
+		}
+		// Native transfers have no contract to match a token filter
+		if f.Token != "" && (txType == models.ETHTransfer || txType == models.ContractCall || txType == models.InternalTx) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Match reports whether a transaction passes the filter
+func (f Filter) Match(tx *models.Transaction) bool {
+	if f.StartBlock > 0 || f.EndBlock > 0 {
+		var block big.Int
+		if _, ok := block.SetString(tx.BlockNumber, 10); !ok {
+			return false
+		}
+		if f.StartBlock > 0 && block.Cmp(big.NewInt(int64(f.StartBlock))) < 0 {
+			return false
+		}
+		if f.EndBlock > 0 && block.Cmp(big.NewInt(int64(f.EndBlock))) > 0 {
+			return false
+		}
+	}
+
+	if !f.FromTime.IsZero() && tx.DateTime.Before(f.FromTime) {
+		return false
+	}
+	if !f.ToTime.IsZero() && !tx.DateTime.Before(f.ToTime) {
+		return false
+	}
+
+	if len(f.Types) > 0 && !f.Types[tx.TransactionType] {
+		return false
+	}
+
+	if f.Token != "" && strings.ToLower(tx.AssetContractAddr) != f.Token {
+		return false
+	}
+
+	if f.MinValue != nil {
+		value := new(big.Rat)
+		if tx.Value != nil {
+			scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tx.AssetDecimals)), nil)
+			value.SetFrac(tx.Value, scale)
+		}
+		if value.Cmp(f.MinValue) < 0 {
+			return false
+		}
+	}
+
+	switch f.Status {
+	case StatusSuccess:
+		// Token and NFT transfers carry "1", they are only listed when successful
+		if tx.Status != "Success" && tx.Status != "1" {
+			return false
+		}
+	case StatusFailed:
+		if tx.Status != "Failed" {
+			return false
+		}
+	}
+
+	if f.Counterparty != "" && strings.ToLower(tx.FromAddress) != f.Counterparty && strings.ToLower(tx.ToAddress) != f.Counterparty {
+		return false
+	}
+
+	return true
+}
+
+// Apply wraps an emit function so only transactions passing the filter reach it
+func (f Filter) Apply(emit func(*models.Transaction) error) func(*models.Transaction) error {
+	if f.IsEmpty() {
+		return emit
+	}
+	return func(tx *models.Transaction) error {
+		if !f.Match(tx) {
+			return nil
+		}
+		return emit(tx)
+	}
+}
@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterSpecRoundTrip(t *testing.T) {
+	specs := []FilterSpec{
+		{},
+		{StartBlock: 100, EndBlock: 200},
+		{FromDate: "2024-01-01", ToDate: "2024-12-31"},
+		{FromDate: "2024-03-01T12:30:00Z", ToDate: "2024-03-02T00:00:00Z"},
+		{Types: "erc20,nft,eth", Status: "failed"},
+		{Token: "0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48", MinValue: "0.25"},
+		{MinValue: "1000", Counterparty: "0x28c6c06298d514db089934071355e5743bf21d60"},
+	}
+
+	for _, spec := range specs {
+		filter, err := ParseFilter(spec)
+		if err != nil {
+			t.Fatalf("%+v: %v", spec, err)
+		}
+		recorded := filter.Spec()
+		again, err := ParseFilter(recorded)
+		if err != nil {
+			t.Fatalf("recorded spec %+v: %v", recorded, err)
+		}
+		if !reflect.DeepEqual(filter.Spec(), again.Spec()) || filter.IsEmpty() != again.IsEmpty() {
+			t.Errorf("%+v was recorded as %+v, which selects something else", spec, recorded)
+		}
+		if (filter.MinValue == nil) != (again.MinValue == nil) || filter.MinValue != nil && filter.MinValue.Cmp(again.MinValue) != 0 {
+			t.Errorf("minimum value %v was recorded as %s", filter.MinValue, recorded.MinValue)
+		}
+		if !filter.FromTime.Equal(again.FromTime) || !filter.ToTime.Equal(again.ToTime) {
+			t.Errorf("dates %v-%v were recorded as %s-%s", filter.FromTime, filter.ToTime, recorded.FromDate, recorded.ToDate)
+		}
+	}
+}
+
+func TestFilterSpecNames(t *testing.T) {
+	filter, err := ParseFilter(FilterSpec{Types: "ERC-20 Transfer, nft, native", ToDate: "2024-12-31", MinValue: "0.50"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FilterSpec{Types: "eth,erc20,erc721", ToDate: "2025-01-01T00:00:00Z", MinValue: "0.5"}
+	if got := filter.Spec(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -4,21 +4,32 @@ import (
 	"crypto-acc-tracking/internal/models"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	WeiPerEth = 1e18
+	WeiPerEth      = 1e18
+	NativeDecimals = 18
 )
 
 // Processor handles transaction data processing and conversion
-type Processor struct{}
+type Processor struct {
+	chain models.Chain
+}
 
 // New creates a new processor instance
 func New() *Processor {
-	return &Processor{}
+	return NewForChain(models.DefaultChain)
+}
+
+// NewForChain creates a new processor instance for the given chain
+func NewForChain(chain models.Chain) *Processor {
+	return &Processor{
+		chain: chain,
+	}
 }
 
 // ProcessNormalTransaction converts Etherscan normal transaction to unified format
@@ -33,7 +44,8 @@ func (p *Processor) ProcessNormalTransaction(tx models.EtherscanNormalTx) (*mode
 		return nil, fmt.Errorf("failed to parse value: %s", tx.Value)
 	}
 
-	gasFee := p.calculateGasFee(tx.GasUsed, tx.GasPrice)
+	gasFeeWei := p.calculateGasFeeWei(tx.GasUsed, tx.GasPrice)
+	gasFee := p.formatEthValue(gasFeeWei)
 	valueFormatted := p.formatEthValue(value)
 
 	transaction := &models.Transaction{
@@ -42,16 +54,19 @@ func (p *Processor) ProcessNormalTransaction(tx models.EtherscanNormalTx) (*mode
 		FromAddress:       tx.From,
 		ToAddress:         tx.To,
 		TransactionType:   models.ETHTransfer,
-		AssetContractAddr: "", // ETH doesn't have a contract address
-		AssetSymbol:       "ETH",
-		AssetName:         "Ethereum",
+		AssetContractAddr: "", // The native asset doesn't have a contract address
+		AssetSymbol:       p.chain.NativeSymbol,
+		AssetName:         p.chain.NativeName,
+		AssetDecimals:     NativeDecimals,
 		TokenID:           "",
 		Value:             value,
 		ValueFormatted:    valueFormatted,
 		GasFeeETH:         gasFee,
+		GasFeeWei:         gasFeeWei,
 		BlockNumber:       tx.BlockNumber,
 		TransactionIndex:  tx.TransactionIndex,
 		Status:            p.getTransactionStatus(tx.IsError, tx.TxReceiptStatus),
+		Chain:             p.chain.Name,
 	}
 
 	// Check if it's a contract interaction
@@ -83,15 +98,19 @@ func (p *Processor) ProcessInternalTransaction(tx models.EtherscanInternalTx) (*
 		ToAddress:         tx.To,
 		TransactionType:   models.InternalTx,
 		AssetContractAddr: tx.ContractAddress,
-		AssetSymbol:       "ETH",
-		AssetName:         "Ethereum",
+		AssetSymbol:       p.chain.NativeSymbol,
+		AssetName:         p.chain.NativeName,
+		AssetDecimals:     NativeDecimals,
 		TokenID:           "",
 		Value:             value,
 		ValueFormatted:    valueFormatted,
 		GasFeeETH:         "0", // Internal transactions don't have gas fees
+		GasFeeWei:         big.NewInt(0),
 		BlockNumber:       tx.BlockNumber,
 		TransactionIndex:  "",
+		LogIndex:          tx.TraceID,
 		Status:            p.getInternalTransactionStatus(tx.IsError),
+		Chain:             p.chain.Name,
 	}
 
 	return transaction, nil
@@ -109,7 +128,8 @@ func (p *Processor) ProcessTokenTransaction(tx models.EtherscanTokenTx) (*models
 		return nil, fmt.Errorf("failed to parse value: %s", tx.Value)
 	}
 
-	gasFee := p.calculateGasFee(tx.GasUsed, tx.GasPrice)
+	gasFeeWei := p.calculateGasFeeWei(tx.GasUsed, tx.GasPrice)
+	gasFee := p.formatEthValue(gasFeeWei)
 
 	// Parse token decimals
 	decimals, err := strconv.Atoi(tx.TokenDecimal)
@@ -128,13 +148,17 @@ func (p *Processor) ProcessTokenTransaction(tx models.EtherscanTokenTx) (*models
 		AssetContractAddr: tx.ContractAddress,
 		AssetSymbol:       tx.TokenSymbol,
 		AssetName:         tx.TokenName,
+		AssetDecimals:     decimals,
 		TokenID:           "",
 		Value:             value,
 		ValueFormatted:    valueFormatted,
 		GasFeeETH:         gasFee,
+		GasFeeWei:         gasFeeWei,
 		BlockNumber:       tx.BlockNumber,
 		TransactionIndex:  tx.TransactionIndex,
+		LogIndex:          tx.LogIndex,
 		Status:            "1", // Token transactions are usually successful if they appear in the list
+		Chain:             p.chain.Name,
 	}
 
 	return transaction, nil
@@ -147,7 +171,8 @@ func (p *Processor) ProcessNFTTransaction(tx models.EtherscanNFTTx) (*models.Tra
 		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
 	}
 
-	gasFee := p.calculateGasFee(tx.GasUsed, tx.GasPrice)
+	gasFeeWei := p.calculateGasFeeWei(tx.GasUsed, tx.GasPrice)
+	gasFee := p.formatEthValue(gasFeeWei)
 
 	transaction := &models.Transaction{
 		Hash:              tx.Hash,
@@ -158,29 +183,73 @@ func (p *Processor) ProcessNFTTransaction(tx models.EtherscanNFTTx) (*models.Tra
 		AssetContractAddr: tx.ContractAddress,
 		AssetSymbol:       tx.TokenSymbol,
 		AssetName:         tx.TokenName,
+		AssetDecimals:     0,
 		TokenID:           tx.TokenID,
 		Value:             big.NewInt(1), // NFTs typically have quantity of 1
 		ValueFormatted:    "1",
 		GasFeeETH:         gasFee,
+		GasFeeWei:         gasFeeWei,
 		BlockNumber:       tx.BlockNumber,
 		TransactionIndex:  tx.TransactionIndex,
+		LogIndex:          tx.LogIndex,
 		Status:            "1", // NFT transactions are usually successful if they appear in the list
+		Chain:             p.chain.Name,
 	}
 
 	return transaction, nil
 }
 
-// calculateGasFee calculates the gas fee in ETH
-func (p *Processor) calculateGasFee(gasUsed, gasPrice string) string {
+// ProcessERC1155Transaction converts an Etherscan ERC-1155 transfer to unified format. The
+// value is the number of tokens of the ID moved, which have no decimals.
+func (p *Processor) ProcessERC1155Transaction(tx models.EtherscanERC1155Tx) (*models.Transaction, error) {
+	timestamp, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	value, ok := new(big.Int).SetString(tx.TokenValue, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse token value: %s", tx.TokenValue)
+	}
+
+	gasFeeWei := p.calculateGasFeeWei(tx.GasUsed, tx.GasPrice)
+	gasFee := p.formatEthValue(gasFeeWei)
+
+	transaction := &models.Transaction{
+		Hash:              tx.Hash,
+		DateTime:          time.Unix(timestamp, 0).UTC(),
+		FromAddress:       tx.From,
+		ToAddress:         tx.To,
+		TransactionType:   models.ERC1155Transfer,
+		AssetContractAddr: tx.ContractAddress,
+		AssetSymbol:       tx.TokenSymbol,
+		AssetName:         tx.TokenName,
+		AssetDecimals:     0,
+		TokenID:           tx.TokenID,
+		Value:             value,
+		ValueFormatted:    value.String(),
+		GasFeeETH:         gasFee,
+		GasFeeWei:         gasFeeWei,
+		BlockNumber:       tx.BlockNumber,
+		TransactionIndex:  tx.TransactionIndex,
+		LogIndex:          tx.LogIndex,
+		Status:            "1", // Like NFT transfers, only successful ones are listed
+		Chain:             p.chain.Name,
+	}
+
+	return transaction, nil
+}
+
+// calculateGasFeeWei calculates the gas fee in wei
+func (p *Processor) calculateGasFeeWei(gasUsed, gasPrice string) *big.Int {
 	gasUsedBig, ok1 := new(big.Int).SetString(gasUsed, 10)
 	gasPriceBig, ok2 := new(big.Int).SetString(gasPrice, 10)
 
 	if !ok1 || !ok2 {
-		return "0"
+		return big.NewInt(0)
 	}
 
-	gasFeeWei := new(big.Int).Mul(gasUsedBig, gasPriceBig)
-	return p.formatEthValue(gasFeeWei)
+	return new(big.Int).Mul(gasUsedBig, gasPriceBig)
 }
 
 // formatEthValue formats wei value to ETH with proper decimal places
@@ -214,6 +283,34 @@ func (p *Processor) formatTokenValue(value *big.Int, decimals int) string {
 	return tokenValue.Text('f', int(decimals))
 }
 
+// FormatUnits formats a raw integer amount as a decimal string with the given decimals
+func FormatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "0"
+	}
+	if decimals <= 0 {
+		return value.String()
+	}
+
+	negative := value.Sign() < 0
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	result := whole
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
 // getTransactionStatus determines transaction status
 func (p *Processor) getTransactionStatus(isError, txReceiptStatus string) string {
 	if isError == "1" {
@@ -236,24 +333,95 @@ func (p *Processor) getInternalTransactionStatus(isError string) string {
 	return "Success"
 }
 
-// SortTransactionsByTime sorts transactions by timestamp in descending order
-func (p *Processor) SortTransactionsByTime(transactions []*models.Transaction) {
-	for i := 0; i < len(transactions)-1; i++ {
-		for j := 0; j < len(transactions)-i-1; j++ {
-			if transactions[j].DateTime.Before(transactions[j+1].DateTime) {
-				transactions[j], transactions[j+1] = transactions[j+1], transactions[j]
-			}
+// SortOrder selects whether transactions are listed oldest or newest first
+type SortOrder string
+
+const (
+	Ascending  SortOrder = "asc"
+	Descending SortOrder = "desc"
+)
+
+// ParseSortOrder validates a sort order name; an empty name means newest first
+func ParseSortOrder(name string) (SortOrder, error) {
+	switch order := SortOrder(strings.ToLower(name)); order {
+	case "", Descending:
+		return Descending, nil
+	case Ascending:
+		return Ascending, nil
+	default:
+		return "", fmt.Errorf("unsupported sort order: %s (use asc or desc)", name)
+	}
+}
+
+// Less reports whether a is listed before b in this order. The zero value lists newest first.
+func (o SortOrder) Less(a, b *models.Transaction) bool {
+	if o == Ascending {
+		return CompareTransactions(a, b) < 0
+	}
+	return CompareTransactions(a, b) > 0
+}
+
+// SortTransactions sorts transactions in canonical chain order, keeping rows that compare
+// equal in their original order
+func (p *Processor) SortTransactions(transactions []*models.Transaction, order SortOrder) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return order.Less(transactions[i], transactions[j])
+	})
+}
+
+// CompareTransactions orders transactions canonically by block time, block number, chain,
+// transaction index and log or trace index. Within one chain this is chain order; across
+// chains it interleaves histories chronologically. Missing indexes sort first.
+func CompareTransactions(a, b *models.Transaction) int {
+	if !a.DateTime.Equal(b.DateTime) {
+		if a.DateTime.Before(b.DateTime) {
+			return -1
 		}
+		return 1
+	}
+	if c := compareIndex(a.BlockNumber, b.BlockNumber); c != 0 {
+		return c
 	}
+	if c := strings.Compare(a.Chain, b.Chain); c != 0 {
+		return c
+	}
+	if c := compareIndex(a.TransactionIndex, b.TransactionIndex); c != 0 {
+		return c
+	}
+	return compareIndex(a.LogIndex, b.LogIndex)
 }
 
-// DeduplicateTransactions removes duplicate transactions based on hash and type
-func (p *Processor) DeduplicateTransactions(transactions []*models.Transaction) []*models.Transaction {
+// compareIndex compares numeric indexes, falling back to string order for values such
+// as trace IDs that are not plain numbers
+func compareIndex(a, b string) int {
+	if a == "" || b == "" {
+		return strings.Compare(a, b)
+	}
+
+	x, errA := strconv.ParseInt(a, 10, 64)
+	y, errB := strconv.ParseInt(b, 10, 64)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DeduplicateTransfers removes rows describing the same transfer, such as a transfer
+// between two tracked wallets that was fetched once for each side
+func (p *Processor) DeduplicateTransfers(transactions []*models.Transaction) []*models.Transaction {
 	seen := make(map[string]bool)
 	var unique []*models.Transaction
 
 	for _, tx := range transactions {
-		key := fmt.Sprintf("%s_%s", tx.Hash, tx.TransactionType)
+		key := TransferKey(tx)
 		if !seen[key] {
 			seen[key] = true
 			unique = append(unique, tx)
@@ -263,6 +431,84 @@ func (p *Processor) DeduplicateTransactions(transactions []*models.Transaction)
 	return unique
 }
 
+// TransferKey identifies a single value movement regardless of which wallet it was fetched
+// for. The log index tells apart identical token transfers of one transaction, such as the
+// payouts of a batch transfer; it is the same whichever wallet the transfer was fetched for.
+func TransferKey(tx *models.Transaction) string {
+	value := ""
+	if tx.Value != nil {
+		value = tx.Value.String()
+	}
+	return strings.Join([]string{
+		tx.Chain,
+		tx.Hash,
+		string(tx.TransactionType),
+		strings.ToLower(tx.FromAddress),
+		strings.ToLower(tx.ToAddress),
+		strings.ToLower(tx.AssetContractAddr),
+		tx.TokenID,
+		value,
+		tx.LogIndex,
+	}, "_")
+}
+
+// BlockDeduplicator drops repeated transfers from a stream in canonical order. Duplicates
+// share a block, so only the keys of the current block are remembered.
+type BlockDeduplicator struct {
+	block string
+	seen  map[string]bool
+}
+
+// NewBlockDeduplicator creates a deduplicator for a sorted transaction stream
+func NewBlockDeduplicator() *BlockDeduplicator {
+	return &BlockDeduplicator{
+		seen: make(map[string]bool),
+	}
+}
+
+// Duplicate reports whether the transfer was already seen in its block
+func (d *BlockDeduplicator) Duplicate(tx *models.Transaction) bool {
+	block := tx.Chain + "_" + tx.BlockNumber
+	if block != d.block {
+		d.block = block
+		d.seen = make(map[string]bool)
+	}
+
+	key := TransferKey(tx)
+	if d.seen[key] {
+		return true
+	}
+	d.seen[key] = true
+	return false
+}
+
+// AssignDirections marks each transaction as incoming, outgoing, a self-transfer or an
+// internal move between the owned addresses
+func (p *Processor) AssignDirections(transactions []*models.Transaction, owned map[string]bool) {
+	for _, tx := range transactions {
+		p.AssignDirection(tx, owned)
+	}
+}
+
+// AssignDirection marks a single transaction relative to the owned addresses
+func (p *Processor) AssignDirection(tx *models.Transaction, owned map[string]bool) {
+	from := strings.ToLower(tx.FromAddress)
+	to := strings.ToLower(tx.ToAddress)
+
+	switch {
+	case owned[from] && owned[to] && from == to:
+		tx.Direction = models.DirectionSelf
+	case owned[from] && owned[to]:
+		tx.Direction = models.DirectionInternalMove
+	case owned[from]:
+		tx.Direction = models.DirectionOut
+	case owned[to]:
+		tx.Direction = models.DirectionIn
+	default:
+		tx.Direction = ""
+	}
+}
+
 // ValidateEthereumAddress validates if the provided string is a valid Ethereum address
 func (p *Processor) ValidateEthereumAddress(address string) bool {
 	if len(address) != 42 {
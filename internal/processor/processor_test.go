@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompareTransactions(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tx := func(block, index, log string) *models.Transaction {
+		return &models.Transaction{DateTime: noon, Chain: "ethereum", BlockNumber: block, TransactionIndex: index, LogIndex: log}
+	}
+
+	tests := []struct {
+		name string
+		a, b *models.Transaction
+		want int
+	}{
+		{"earlier time first", &models.Transaction{DateTime: noon.Add(-time.Second), BlockNumber: "9"}, tx("1", "0", ""), -1},
+		{"lower block first", tx("99", "5", ""), tx("100", "0", ""), -1},
+		{"same block by tx index", tx("100", "2", "0"), tx("100", "10", "0"), -1},
+		{"same tx index by log index", tx("100", "3", "7"), tx("100", "3", "12"), -1},
+		{"tx index before log index", tx("100", "4", "0"), tx("100", "3", "99"), 1},
+		{"missing log index first", tx("100", "3", ""), tx("100", "3", "0"), -1},
+		{"trace ids as strings", tx("100", "3", "0_1"), tx("100", "3", "0_2"), -1},
+		{"chain breaks block ties", &models.Transaction{DateTime: noon, Chain: "arbitrum", BlockNumber: "100"}, tx("100", "0", ""), -1},
+		{"equal", tx("100", "3", "7"), tx("100", "3", "7"), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CompareTransactions(test.a, test.b); got != test.want {
+				t.Errorf("CompareTransactions(a, b) = %d, want %d", got, test.want)
+			}
+			if got := CompareTransactions(test.b, test.a); got != -test.want {
+				t.Errorf("CompareTransactions(b, a) = %d, want %d", got, -test.want)
+			}
+		})
+	}
+}
+
+func TestSortTransactionsStable(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var transactions []*models.Transaction
+	for i := 0; i < 10; i++ {
+		// Two legs per transaction that compare equal, in reverse block order
+		for leg := 0; leg < 2; leg++ {
+			transactions = append(transactions, &models.Transaction{
+				Hash: fmt.Sprintf("%d-%d", i, leg), DateTime: noon, BlockNumber: strconv.Itoa(10 - i), TransactionIndex: "0", LogIndex: "1",
+			})
+		}
+	}
+
+	New().SortTransactions(transactions, Ascending)
+	for i, tx := range transactions {
+		if want := fmt.Sprintf("%d-%d", 9-i/2, i%2); tx.Hash != want {
+			t.Fatalf("row %d is %s, want %s", i, tx.Hash, want)
+		}
+	}
+}
+
+// randomTransactions returns n transactions in random order with many sharing a block
+func randomTransactions(n int) []*models.Transaction {
+	r := rand.New(rand.NewSource(1))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]*models.Transaction, n)
+	for i := range transactions {
+		block := r.Intn(n / 10)
+		transactions[i] = &models.Transaction{
+			Hash:             fmt.Sprintf("0x%x", i),
+			DateTime:         start.Add(time.Duration(block) * 12 * time.Second),
+			Chain:            "ethereum",
+			BlockNumber:      strconv.Itoa(block),
+			TransactionIndex: strconv.Itoa(r.Intn(200)),
+			LogIndex:         strconv.Itoa(r.Intn(50)),
+		}
+	}
+	return transactions
+}
+
+func BenchmarkSortTransactions(b *testing.B) {
+	transactions := randomTransactions(100000)
+	work := make([]*models.Transaction, len(transactions))
+	p := New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(work, transactions)
+		b.StartTimer()
+		p.SortTransactions(work, Ascending)
+	}
+}
+
+func TestDeduplicateTransfers(t *testing.T) {
+	swapOut := &models.Transaction{
+		Hash: "0xswap", TransactionType: models.ERC20Transfer, BlockNumber: "100",
+		FromAddress: "0xwallet", ToAddress: "0xpool", AssetContractAddr: "0xusdc", Value: big.NewInt(1000),
+	}
+	swapIn := &models.Transaction{
+		Hash: "0xswap", TransactionType: models.ERC20Transfer, BlockNumber: "100",
+		FromAddress: "0xpool", ToAddress: "0xwallet", AssetContractAddr: "0xweth", Value: big.NewInt(5),
+	}
+	// The same transfer fetched for the other side of a move between tracked wallets
+	repeated := *swapOut
+	repeated.FromAddress = "0xWALLET"
+
+	unique := New().DeduplicateTransfers([]*models.Transaction{swapOut, swapIn, &repeated})
+	if len(unique) != 2 {
+		t.Fatalf("got %d transactions, want both legs of the swap", len(unique))
+	}
+	if unique[0] != swapOut || unique[1] != swapIn {
+		t.Errorf("got %v, want the first occurrence of each transfer in order", unique)
+	}
+}
+
+func TestIdenticalTransfersInOneTransaction(t *testing.T) {
+	// A batch payout sending the same amount of the same token to the same address twice
+	payout := func(logIndex string) *models.Transaction {
+		return &models.Transaction{
+			Chain: "ethereum", Hash: "0xbatch", TransactionType: models.ERC20Transfer, BlockNumber: "100",
+			FromAddress: "0xdistributor", ToAddress: "0xwallet", AssetContractAddr: "0xusdc",
+			Value: big.NewInt(1000), LogIndex: logIndex,
+		}
+	}
+	first, second := payout("7"), payout("8")
+	// The first payout fetched again for another tracked wallet
+	again := payout("7")
+
+	unique := New().DeduplicateTransfers([]*models.Transaction{first, second, again})
+	if len(unique) != 2 || unique[0] != first || unique[1] != second {
+		t.Errorf("DeduplicateTransfers kept %d rows, want both payouts once", len(unique))
+	}
+
+	d := NewBlockDeduplicator()
+	var kept int
+	for _, tx := range []*models.Transaction{first, second, again} {
+		if !d.Duplicate(tx) {
+			kept++
+		}
+	}
+	if kept != 2 {
+		t.Errorf("BlockDeduplicator kept %d rows, want both payouts once", kept)
+	}
+}
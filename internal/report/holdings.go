@@ -0,0 +1,234 @@
+package report
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cutoff identifies the point in history at which holdings are computed.
+// A zero Block or Time means no limit on that dimension.
+type Cutoff struct {
+	Block int
+	Time  time.Time
+}
+
+// Includes reports whether a transaction happened at or before the cutoff
+func (c Cutoff) Includes(tx *models.Transaction) bool {
+	if c.Block > 0 {
+		block, err := strconv.Atoi(tx.BlockNumber)
+		if err != nil || block > c.Block {
+			return false
+		}
+	}
+	if !c.Time.IsZero() && tx.DateTime.After(c.Time) {
+		return false
+	}
+	return true
+}
+
+// Holding represents the quantity of one fungible asset held at the cutoff
+type Holding struct {
+	Chain           string
+	ContractAddress string
+	Symbol          string
+	Name            string
+	Decimals        int
+	Quantity        *big.Int
+	Price           *big.Float
+	FiatValue       *big.Float
+}
+
+// Amount returns the quantity formatted with the asset decimals
+func (h *Holding) Amount() string {
+	return processor.FormatUnits(h.Quantity, h.Decimals)
+}
+
+// NFTItem represents a non-fungible or multi-token ID held at the cutoff, with the number
+// of tokens of the ID held: always 1 for ERC-721, any positive count for ERC-1155
+type NFTItem struct {
+	Chain           string
+	ContractAddress string
+	Symbol          string
+	Name            string
+	TokenID         string
+	Quantity        *big.Int
+}
+
+// Snapshot represents the holdings of a set of addresses at a point in history
+type Snapshot struct {
+	Addresses  []string
+	Cutoff     Cutoff
+	Holdings   []*Holding
+	NFTs       []NFTItem
+	Currency   string
+	TotalValue *big.Float
+}
+
+// ComputeHoldings replays the processed history of the owned addresses up to the cutoff.
+// Every holding, token IDs included, is a signed sum of the transfers in and out, so the
+// history may be given in any order.
+func ComputeHoldings(transactions []*models.Transaction, owned map[string]bool, cutoff Cutoff) *Snapshot {
+	holdings := make(map[string]*Holding)
+	nfts := make(map[string]*NFTItem)
+
+	for _, tx := range transactions {
+		if !cutoff.Includes(tx) {
+			continue
+		}
+
+		from := owned[strings.ToLower(tx.FromAddress)]
+		to := owned[strings.ToLower(tx.ToAddress)]
+
+		// Gas is paid by the sender of a normal transaction even when it fails
+		if from && isNormalTransaction(tx) && tx.GasFeeWei != nil {
+			native := nativeHolding(holdings, tx)
+			native.Quantity.Sub(native.Quantity, tx.GasFeeWei)
+		}
+
+		if tx.Status == "Failed" || from == to {
+			continue
+		}
+
+		if tx.TransactionType == models.ERC721Transfer || tx.TransactionType == models.ERC1155Transfer {
+			item := nftItem(nfts, tx)
+			if to {
+				item.Quantity.Add(item.Quantity, tokenCount(tx))
+			} else {
+				item.Quantity.Sub(item.Quantity, tokenCount(tx))
+			}
+			continue
+		}
+
+		if tx.Value == nil {
+			continue
+		}
+
+		var holding *Holding
+		if tx.TransactionType == models.ERC20Transfer {
+			holding = tokenHolding(holdings, tx)
+		} else {
+			holding = nativeHolding(holdings, tx)
+		}
+
+		if to {
+			holding.Quantity.Add(holding.Quantity, tx.Value)
+		} else {
+			holding.Quantity.Sub(holding.Quantity, tx.Value)
+		}
+	}
+
+	snapshot := &Snapshot{Cutoff: cutoff}
+	for address := range owned {
+		snapshot.Addresses = append(snapshot.Addresses, address)
+	}
+	sort.Strings(snapshot.Addresses)
+
+	for _, holding := range holdings {
+		if holding.Quantity.Sign() != 0 {
+			snapshot.Holdings = append(snapshot.Holdings, holding)
+		}
+	}
+	sort.Slice(snapshot.Holdings, func(i, j int) bool {
+		a, b := snapshot.Holdings[i], snapshot.Holdings[j]
+		if a.Chain != b.Chain {
+			return a.Chain < b.Chain
+		}
+		if (a.ContractAddress == "") != (b.ContractAddress == "") {
+			return a.ContractAddress == ""
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	for _, item := range nfts {
+		if item.Quantity.Sign() > 0 {
+			snapshot.NFTs = append(snapshot.NFTs, *item)
+		}
+	}
+	sort.Slice(snapshot.NFTs, func(i, j int) bool {
+		a, b := snapshot.NFTs[i], snapshot.NFTs[j]
+		if a.ContractAddress != b.ContractAddress {
+			return a.ContractAddress < b.ContractAddress
+		}
+		return a.TokenID < b.TokenID
+	})
+
+	return snapshot
+}
+
+// isNormalTransaction reports whether the row comes from the normal transaction list
+func isNormalTransaction(tx *models.Transaction) bool {
+	return tx.TransactionType == models.ETHTransfer || tx.TransactionType == models.ContractCall
+}
+
+// nativeHolding returns the native asset holding for the transaction chain
+func nativeHolding(holdings map[string]*Holding, tx *models.Transaction) *Holding {
+	key := tx.Chain + "_"
+	holding, ok := holdings[key]
+	if !ok {
+		chain, err := models.LookupChain(tx.Chain)
+		if err != nil {
+			chain = models.DefaultChain
+		}
+		holding = &Holding{
+			Chain:    tx.Chain,
+			Symbol:   chain.NativeSymbol,
+			Name:     chain.NativeName,
+			Decimals: processor.NativeDecimals,
+			Quantity: new(big.Int),
+		}
+		holdings[key] = holding
+	}
+	return holding
+}
+
+// nftItem returns the item of the token ID moved by the transaction
+func nftItem(nfts map[string]*NFTItem, tx *models.Transaction) *NFTItem {
+	contract := strings.ToLower(tx.AssetContractAddr)
+	key := tx.Chain + "_" + contract + "_" + tx.TokenID
+	item, ok := nfts[key]
+	if !ok {
+		item = &NFTItem{
+			Chain:           tx.Chain,
+			ContractAddress: contract,
+			Symbol:          tx.AssetSymbol,
+			Name:            tx.AssetName,
+			TokenID:         tx.TokenID,
+			Quantity:        new(big.Int),
+		}
+		nfts[key] = item
+	}
+	return item
+}
+
+// tokenCount returns the number of tokens of the ID a transfer moves: its value for
+// ERC-1155, one for ERC-721
+func tokenCount(tx *models.Transaction) *big.Int {
+	if tx.TransactionType == models.ERC1155Transfer && tx.Value != nil {
+		return tx.Value
+	}
+	return big.NewInt(1)
+}
+
+// tokenHolding returns the holding for the token contract of the transaction
+func tokenHolding(holdings map[string]*Holding, tx *models.Transaction) *Holding {
+	contract := strings.ToLower(tx.AssetContractAddr)
+	key := tx.Chain + "_" + contract
+	holding, ok := holdings[key]
+	if !ok {
+		holding = &Holding{
+			Chain:           tx.Chain,
+			ContractAddress: contract,
+			Symbol:          tx.AssetSymbol,
+			Name:            tx.AssetName,
+			Decimals:        tx.AssetDecimals,
+			Quantity:        new(big.Int),
+		}
+		holdings[key] = holding
+	}
+	return holding
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"math/big"
+	"testing"
+)
+
+const (
+	owner    = "0x1111111111111111111111111111111111111111"
+	other    = "0x2222222222222222222222222222222222222222"
+	nftToken = "0x3333333333333333333333333333333333333333"
+)
+
+func nftTransfer(txType models.TransactionType, block, from, to, tokenID string, value int64) *models.Transaction {
+	tx := &models.Transaction{
+		Chain:             "ethereum",
+		Hash:              "0x" + block,
+		BlockNumber:       block,
+		FromAddress:       from,
+		ToAddress:         to,
+		AssetContractAddr: nftToken,
+		AssetSymbol:       "ART",
+		TokenID:           tokenID,
+		TransactionType:   txType,
+		Status:            "Success",
+	}
+	if value > 0 {
+		tx.Value = big.NewInt(value)
+	}
+	return tx
+}
+
+func reversed(transactions []*models.Transaction) []*models.Transaction {
+	out := make([]*models.Transaction, len(transactions))
+	for i, tx := range transactions {
+		out[len(transactions)-1-i] = tx
+	}
+	return out
+}
+
+func TestNFTInventoryIgnoresOrder(t *testing.T) {
+	history := []*models.Transaction{
+		nftTransfer(models.ERC721Transfer, "100", other, owner, "1", 0),
+		nftTransfer(models.ERC721Transfer, "200", owner, other, "1", 0),
+		nftTransfer(models.ERC721Transfer, "300", other, owner, "2", 0),
+	}
+	owned := map[string]bool{owner: true}
+
+	for name, transactions := range map[string][]*models.Transaction{
+		"oldest first": history,
+		"newest first": reversed(history),
+	} {
+		t.Run(name, func(t *testing.T) {
+			nfts := ComputeHoldings(transactions, owned, Cutoff{}).NFTs
+			if len(nfts) != 1 || nfts[0].TokenID != "2" || nfts[0].Quantity.Int64() != 1 {
+				t.Errorf("got %+v, want only token 2", nfts)
+			}
+		})
+	}
+}
+
+func TestERC1155Quantities(t *testing.T) {
+	history := []*models.Transaction{
+		nftTransfer(models.ERC1155Transfer, "100", other, owner, "7", 10),
+		nftTransfer(models.ERC1155Transfer, "200", owner, other, "7", 3),
+		nftTransfer(models.ERC1155Transfer, "300", other, owner, "8", 2),
+		nftTransfer(models.ERC1155Transfer, "400", owner, other, "8", 2),
+	}
+	owned := map[string]bool{owner: true}
+
+	for name, transactions := range map[string][]*models.Transaction{
+		"oldest first": history,
+		"newest first": reversed(history),
+	} {
+		t.Run(name, func(t *testing.T) {
+			nfts := ComputeHoldings(transactions, owned, Cutoff{}).NFTs
+			if len(nfts) != 1 {
+				t.Fatalf("got %d items, want 1: %+v", len(nfts), nfts)
+			}
+			if nfts[0].TokenID != "7" || nfts[0].Quantity.Int64() != 7 {
+				t.Errorf("got token %s x%s, want token 7 x7", nfts[0].TokenID, nfts[0].Quantity)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"crypto-acc-tracking/internal/output"
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Print writes the snapshot to stdout, including balance checks when present
+func (s *Snapshot) Print(checks []BalanceCheck, includeNFTs bool) {
+	fmt.Printf("\n📦 Holdings Snapshot: %s\n", strings.Join(s.Addresses, ", "))
+	if s.Cutoff.Block > 0 {
+		fmt.Printf("   At Block: %d\n", s.Cutoff.Block)
+	}
+	if !s.Cutoff.Time.IsZero() {
+		fmt.Printf("   At Time: %s\n", s.Cutoff.Time.Format("2006-01-02 15:04:05 UTC"))
+	}
+
+	fmt.Printf("\n💰 Assets:\n")
+	if len(s.Holdings) == 0 {
+		fmt.Printf("   (none)\n")
+	}
+	for _, holding := range s.Holdings {
+		line := fmt.Sprintf("   %s %s", holding.Amount(), holding.Symbol)
+		if holding.ContractAddress != "" {
+			line += fmt.Sprintf(" [%s]", holding.ContractAddress)
+		}
+		if len(s.chains()) > 1 {
+			line += fmt.Sprintf(" on %s", holding.Chain)
+		}
+		if holding.FiatValue != nil {
+			line += fmt.Sprintf(" = %s %s", holding.FiatValue.Text('f', 2), s.Currency)
+		}
+		fmt.Println(line)
+	}
+	if s.TotalValue != nil {
+		fmt.Printf("   Total Value: %s %s\n", s.TotalValue.Text('f', 2), s.Currency)
+	}
+
+	if includeNFTs {
+		fmt.Printf("\n🖼️  NFT Inventory:\n")
+		if len(s.NFTs) == 0 {
+			fmt.Printf("   (none)\n")
+		}
+		for _, item := range s.NFTs {
+			line := fmt.Sprintf("   %s #%s", item.Symbol, item.TokenID)
+			if item.Quantity.Cmp(big.NewInt(1)) != 0 {
+				line += fmt.Sprintf(" x%s", item.Quantity)
+			}
+			fmt.Printf("%s [%s]\n", line, item.ContractAddress)
+		}
+	}
+
+	if len(checks) > 0 {
+		fmt.Printf("\n🔎 Etherscan Cross-Check:\n")
+		for _, check := range checks {
+			status := "✅"
+			detail := check.OnChain
+			if !check.Match {
+				status = "⚠️ "
+				detail = check.Note
+				if check.OnChain != "" {
+					detail = fmt.Sprintf("on-chain %s, %s", check.OnChain, check.Note)
+				}
+			}
+			fmt.Printf("   %s %s: %s\n", status, check.Holding.Symbol, detail)
+		}
+	}
+}
+
+// WriteCSV writes the fungible holdings, and optionally the NFT inventory, to a CSV file
+func (s *Snapshot) WriteCSV(filename string, includeNFTs bool) error {
+	file, err := output.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Abort()
+
+	writer := csv.NewWriter(file)
+
+	header := []string{
+		"Chain",
+		"Asset Contract Address",
+		"Asset Symbol",
+		"Asset Name",
+		"Token ID",
+		"Quantity",
+		"Price",
+		"Fiat Value",
+		"Currency",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, holding := range s.Holdings {
+		price, value := "", ""
+		if holding.FiatValue != nil {
+			price = holding.Price.Text('f', -1)
+			value = holding.FiatValue.Text('f', 2)
+		}
+		record := []string{
+			holding.Chain,
+			holding.ContractAddress,
+			holding.Symbol,
+			holding.Name,
+			"",
+			holding.Amount(),
+			price,
+			value,
+			s.Currency,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	if includeNFTs {
+		for _, item := range s.NFTs {
+			record := []string{
+				item.Chain,
+				item.ContractAddress,
+				item.Symbol,
+				item.Name,
+				item.TokenID,
+				item.Quantity.String(),
+				"",
+				"",
+				"",
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to save CSV file: %w", err)
+	}
+
+	return nil
+}
+
+// chains returns the distinct chains present in the snapshot
+func (s *Snapshot) chains() map[string]bool {
+	chains := make(map[string]bool)
+	for _, holding := range s.Holdings {
+		chains[holding.Chain] = true
+	}
+	return chains
+}
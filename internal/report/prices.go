@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Prices maps asset symbols or contract addresses to a fiat price per unit
+type Prices map[string]*big.Float
+
+// LoadPrices reads a JSON object of asset symbol or contract address to unit price
+func LoadPrices(filename string) (Prices, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prices file: %w", err)
+	}
+
+	var raw map[string]json.Number
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse prices file: %w", err)
+	}
+
+	prices := make(Prices)
+	for asset, number := range raw {
+		price, ok := new(big.Float).SetString(number.String())
+		if !ok {
+			return nil, fmt.Errorf("invalid price for %s: %s", asset, number)
+		}
+		prices[strings.ToLower(asset)] = price
+	}
+
+	return prices, nil
+}
+
+// lookup returns the price of a holding, preferring the contract address over the symbol
+func (p Prices) lookup(h *Holding) (*big.Float, bool) {
+	if h.ContractAddress != "" {
+		if price, ok := p[h.ContractAddress]; ok {
+			return price, true
+		}
+	}
+	price, ok := p[strings.ToLower(h.Symbol)]
+	return price, ok
+}
+
+// ApplyPrices values every holding with a known price and totals the snapshot
+func (s *Snapshot) ApplyPrices(prices Prices, currency string) {
+	s.Currency = currency
+	s.TotalValue = new(big.Float)
+
+	for _, holding := range s.Holdings {
+		price, ok := prices.lookup(holding)
+		if !ok {
+			continue
+		}
+
+		quantity, _ := new(big.Float).SetString(holding.Amount())
+		holding.Price = price
+		holding.FiatValue = new(big.Float).Mul(quantity, price)
+		s.TotalValue.Add(s.TotalValue, holding.FiatValue)
+	}
+}
@@ -0,0 +1,270 @@
+package report
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"encoding/csv"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	second = "0x4444444444444444444444444444444444444444"
+	usdc   = "0x5555555555555555555555555555555555555555"
+)
+
+var day = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// wei returns n ether in wei
+func wei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+func ethTransfer(block int, from, to string, value, gas *big.Int) *models.Transaction {
+	return &models.Transaction{
+		Chain:           "ethereum",
+		BlockNumber:     big.NewInt(int64(block)).String(),
+		DateTime:        day.AddDate(0, 0, block/100),
+		FromAddress:     from,
+		ToAddress:       to,
+		Value:           value,
+		GasFeeWei:       gas,
+		TransactionType: models.ETHTransfer,
+		Status:          "Success",
+	}
+}
+
+func failed(tx *models.Transaction) *models.Transaction {
+	tx.Status = "Failed"
+	return tx
+}
+
+func internalMove(block int, from, to string, value *big.Int) *models.Transaction {
+	tx := ethTransfer(block, from, to, value, nil)
+	tx.TransactionType = models.InternalTx
+	return tx
+}
+
+func tokenTransfer(block int, from, to string, value int64) *models.Transaction {
+	tx := ethTransfer(block, from, to, big.NewInt(value), nil)
+	tx.TransactionType = models.ERC20Transfer
+	tx.AssetContractAddr = usdc
+	tx.AssetSymbol = "USDC"
+	tx.AssetDecimals = 6
+	return tx
+}
+
+// amounts returns the formatted quantity of every holding by symbol
+func amounts(s *Snapshot) map[string]string {
+	out := make(map[string]string)
+	for _, holding := range s.Holdings {
+		out[holding.Symbol] = holding.Amount()
+	}
+	return out
+}
+
+// inventory returns the quantity of every token ID held
+func inventory(s *Snapshot) map[string]string {
+	out := make(map[string]string)
+	for _, item := range s.NFTs {
+		out[item.TokenID] = item.Quantity.String()
+	}
+	return out
+}
+
+func TestComputeHoldings(t *testing.T) {
+	tests := []struct {
+		name         string
+		transactions []*models.Transaction
+		owned        []string
+		cutoff       Cutoff
+		holdings     map[string]string
+		nfts         map[string]string
+	}{
+		{
+			name: "receive and send pays gas",
+			transactions: []*models.Transaction{
+				ethTransfer(100, other, owner, wei(5), big.NewInt(1e15)),
+				ethTransfer(200, owner, other, wei(2), big.NewInt(1e15)),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{"ETH": "2.999"},
+		},
+		{
+			name: "failed transaction only pays gas",
+			transactions: []*models.Transaction{
+				ethTransfer(100, other, owner, wei(5), nil),
+				failed(ethTransfer(200, owner, other, wei(2), big.NewInt(1e15))),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{"ETH": "4.999"},
+		},
+		{
+			name: "failed incoming transaction is ignored",
+			transactions: []*models.Transaction{
+				failed(ethTransfer(100, other, owner, wei(5), big.NewInt(1e15))),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{},
+		},
+		{
+			name: "internal transactions move the native asset",
+			transactions: []*models.Transaction{
+				internalMove(100, other, owner, wei(3)),
+				internalMove(200, owner, other, wei(1)),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{"ETH": "2"},
+		},
+		{
+			name: "moves between owned addresses only pay gas",
+			transactions: []*models.Transaction{
+				ethTransfer(100, other, owner, wei(5), nil),
+				ethTransfer(200, owner, second, wei(2), big.NewInt(1e15)),
+			},
+			owned:    []string{owner, second},
+			holdings: map[string]string{"ETH": "4.999"},
+		},
+		{
+			name: "tokens",
+			transactions: []*models.Transaction{
+				tokenTransfer(100, other, owner, 2500000),
+				tokenTransfer(200, owner, other, 500000),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{"USDC": "2"},
+		},
+		{
+			name: "at block",
+			transactions: []*models.Transaction{
+				ethTransfer(100, other, owner, wei(5), nil),
+				ethTransfer(200, other, owner, wei(1), nil),
+				ethTransfer(201, other, owner, wei(1), nil),
+			},
+			owned:    []string{owner},
+			cutoff:   Cutoff{Block: 200},
+			holdings: map[string]string{"ETH": "6"},
+		},
+		{
+			name: "at date",
+			transactions: []*models.Transaction{
+				ethTransfer(100, other, owner, wei(5), nil),
+				ethTransfer(200, other, owner, wei(1), nil),
+				ethTransfer(300, other, owner, wei(1), nil),
+			},
+			owned:    []string{owner},
+			cutoff:   Cutoff{Time: day.AddDate(0, 0, 2)},
+			holdings: map[string]string{"ETH": "6"},
+		},
+		{
+			name: "ERC-721",
+			transactions: []*models.Transaction{
+				nftTransfer(models.ERC721Transfer, "100", other, owner, "1", 0),
+				nftTransfer(models.ERC721Transfer, "100", other, owner, "2", 0),
+				nftTransfer(models.ERC721Transfer, "200", owner, other, "2", 0),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{},
+			nfts:     map[string]string{"1": "1"},
+		},
+		{
+			name: "ERC-1155",
+			transactions: []*models.Transaction{
+				nftTransfer(models.ERC1155Transfer, "100", other, owner, "5", 40),
+				nftTransfer(models.ERC1155Transfer, "200", owner, other, "5", 15),
+			},
+			owned:    []string{owner},
+			holdings: map[string]string{},
+			nfts:     map[string]string{"5": "25"},
+		},
+		{
+			name: "NFTs at block",
+			transactions: []*models.Transaction{
+				nftTransfer(models.ERC1155Transfer, "100", other, owner, "5", 40),
+				nftTransfer(models.ERC1155Transfer, "200", owner, other, "5", 40),
+			},
+			owned:    []string{owner},
+			cutoff:   Cutoff{Block: 150},
+			holdings: map[string]string{},
+			nfts:     map[string]string{"5": "40"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owned := make(map[string]bool)
+			for _, address := range tt.owned {
+				owned[address] = true
+			}
+			snapshot := ComputeHoldings(tt.transactions, owned, tt.cutoff)
+
+			if got := amounts(snapshot); !reflect.DeepEqual(got, tt.holdings) {
+				t.Errorf("holdings %v, want %v", got, tt.holdings)
+			}
+			want := tt.nfts
+			if want == nil {
+				want = map[string]string{}
+			}
+			if got := inventory(snapshot); !reflect.DeepEqual(got, want) {
+				t.Errorf("NFTs %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyPrices(t *testing.T) {
+	snapshot := ComputeHoldings([]*models.Transaction{
+		ethTransfer(100, other, owner, wei(2), nil),
+		tokenTransfer(100, other, owner, 3000000),
+	}, map[string]bool{owner: true}, Cutoff{})
+
+	snapshot.ApplyPrices(Prices{
+		"eth": big.NewFloat(2000),
+		usdc:  big.NewFloat(0.5),
+		"dai": big.NewFloat(1),
+	}, "EUR")
+
+	if got := snapshot.TotalValue.Text('f', 2); got != "4001.50" {
+		t.Errorf("total %s, want 4001.50", got)
+	}
+	for _, holding := range snapshot.Holdings {
+		if holding.FiatValue == nil {
+			t.Errorf("%s has no value", holding.Symbol)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	snapshot := ComputeHoldings([]*models.Transaction{
+		ethTransfer(100, other, owner, wei(2), nil),
+		nftTransfer(models.ERC1155Transfer, "100", other, owner, "5", 40),
+	}, map[string]bool{owner: true}, Cutoff{})
+	snapshot.ApplyPrices(Prices{"eth": big.NewFloat(2000)}, "USD")
+
+	filename := filepath.Join(t.TempDir(), "holdings.csv")
+	if err := snapshot.WriteCSV(filename, true); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"Chain", "Asset Contract Address", "Asset Symbol", "Asset Name", "Token ID", "Quantity", "Price", "Fiat Value", "Currency"},
+		{"ethereum", "", "ETH", "Ethereum", "", "2", "2000", "4000.00", "USD"},
+		{"ethereum", nftToken, "ART", "", "5", "40", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got\n%v\nwant\n%v", records, want)
+	}
+}
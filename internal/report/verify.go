@@ -0,0 +1,62 @@
+package report
+
+import (
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/processor"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// BalanceCheck compares a replayed holding with the balance reported by Etherscan
+type BalanceCheck struct {
+	Holding *Holding
+	OnChain string
+	Match   bool
+	Note    string
+}
+
+// Verify cross-checks every fungible holding of a single address against Etherscan's
+// historical balance endpoints. These endpoints require an API Pro key, so when the
+// first lookup is rejected, or the key turns out to be invalid, the remaining holdings are
+// reported as unavailable.
+func (s *Snapshot) Verify(client *etherscan.Client, address string) []BalanceCheck {
+	var checks []BalanceCheck
+	var unavailable error
+
+	for _, holding := range s.Holdings {
+		check := BalanceCheck{Holding: holding}
+
+		if unavailable != nil {
+			check.Note = fmt.Sprintf("unavailable: %v", unavailable)
+			checks = append(checks, check)
+			continue
+		}
+
+		var balance *big.Int
+		var err error
+		if holding.ContractAddress == "" {
+			balance, err = client.GetBalanceAtBlock(address, s.Cutoff.Block)
+		} else {
+			balance, err = client.GetTokenBalanceAtBlock(holding.ContractAddress, address, s.Cutoff.Block)
+		}
+
+		if err != nil {
+			if len(checks) == 0 || errors.Is(err, etherscan.ErrInvalidAPIKey) {
+				unavailable = err
+			}
+			check.Note = fmt.Sprintf("unavailable: %v", err)
+			checks = append(checks, check)
+			continue
+		}
+
+		check.OnChain = processor.FormatUnits(balance, holding.Decimals)
+		check.Match = balance.Cmp(holding.Quantity) == 0
+		if !check.Match {
+			check.Note = "replayed balance differs from on-chain balance"
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"crypto-acc-tracking/api/trackerpb"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/report"
+	"crypto-acc-tracking/internal/tracker"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// transactionTypes maps transaction types to their protobuf enum values
+var transactionTypes = map[models.TransactionType]trackerpb.TransactionType{
+	models.ETHTransfer:     trackerpb.TransactionType_TRANSACTION_TYPE_ETH_TRANSFER,
+	models.ERC20Transfer:   trackerpb.TransactionType_TRANSACTION_TYPE_ERC20_TRANSFER,
+	models.ERC721Transfer:  trackerpb.TransactionType_TRANSACTION_TYPE_ERC721_TRANSFER,
+	models.ERC1155Transfer: trackerpb.TransactionType_TRANSACTION_TYPE_ERC1155_TRANSFER,
+	models.ContractCall:    trackerpb.TransactionType_TRANSACTION_TYPE_CONTRACT_CALL,
+	models.InternalTx:      trackerpb.TransactionType_TRANSACTION_TYPE_INTERNAL_TRANSFER,
+}
+
+// directionValues maps directions to their protobuf enum values
+var directionValues = map[models.Direction]trackerpb.Direction{
+	models.DirectionIn:           trackerpb.Direction_DIRECTION_IN,
+	models.DirectionOut:          trackerpb.Direction_DIRECTION_OUT,
+	models.DirectionSelf:         trackerpb.Direction_DIRECTION_SELF,
+	models.DirectionInternalMove: trackerpb.Direction_DIRECTION_INTERNAL_MOVE,
+}
+
+// GRPCService implements the Tracker gRPC service. Every call fetches with a tracker from
+// the factory, so it shares the rate limit and store of the REST jobs.
+type GRPCService struct {
+	trackerpb.UnimplementedTrackerServer
+
+	newTracker TrackerFactory
+}
+
+// NewGRPCService creates the gRPC service fetching with trackers from the factory
+func NewGRPCService(newTracker TrackerFactory) *GRPCService {
+	return &GRPCService{newTracker: newTracker}
+}
+
+// TrackWallet streams the transactions of an address as their pages are processed,
+// dropping transfers already sent
+func (s *GRPCService) TrackWallet(request *trackerpb.TrackWalletRequest, stream trackerpb.Tracker_TrackWalletServer) error {
+	t, chain, address, err := s.tracker(request.Address, request.Chain, request.Filter)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	t.SetContext(ctx)
+	proc := processor.NewForChain(chain)
+	owned := map[string]bool{address: true}
+	seen := make(map[string]bool)
+	err = t.StreamTransactions(address, func(tx *models.Transaction) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := processor.TransferKey(tx)
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+
+		proc.AssignDirection(tx, owned)
+		return stream.Send(transactionMessage(tx))
+	})
+	return fetchError(ctx, err)
+}
+
+// GetSummary counts the history of an address by type, direction and asset
+func (s *GRPCService) GetSummary(ctx context.Context, request *trackerpb.SummaryRequest) (*trackerpb.Summary, error) {
+	t, chain, address, err := s.tracker(request.Address, request.Chain, request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := fetch(ctx, t, chain, address)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := exporter.NewSummaryCounter()
+	summary := &trackerpb.Summary{
+		Address:          address,
+		Chain:            chain.Name,
+		TransactionTypes: make(map[string]int64),
+		Directions:       make(map[string]int64),
+	}
+	var first, last time.Time
+	for _, tx := range transactions {
+		counter.Add(tx)
+		if first.IsZero() || tx.DateTime.Before(first) {
+			first = tx.DateTime
+		}
+		if tx.DateTime.After(last) {
+			last = tx.DateTime
+		}
+	}
+
+	counts := counter.Summary("")
+	summary.TotalTransactions = int64(counts["total_transactions"].(int))
+	summary.UniqueAssets = int64(counts["unique_assets"].(int))
+	for txType, count := range counts["transaction_types"].(map[models.TransactionType]int) {
+		summary.TransactionTypes[string(txType)] = int64(count)
+	}
+	for direction, count := range counts["directions"].(map[models.Direction]int) {
+		if direction == "" {
+			direction = "Other"
+		}
+		summary.Directions[string(direction)] = int64(count)
+	}
+	if len(transactions) > 0 {
+		summary.FirstTransaction = timestamppb.New(first)
+		summary.LastTransaction = timestamppb.New(last)
+	}
+	return summary, nil
+}
+
+// GetBalances replays the history of an address into holdings at the requested cutoff
+func (s *GRPCService) GetBalances(ctx context.Context, request *trackerpb.BalancesRequest) (*trackerpb.Balances, error) {
+	t, chain, address, err := s.tracker(request.Address, request.Chain, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := report.Cutoff{Block: int(request.AtBlock)}
+	if request.AtTime != nil {
+		if err := request.AtTime.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid at_time: %v", err)
+		}
+		cutoff.Time = request.AtTime.AsTime()
+	}
+	if cutoff.Block == 0 && cutoff.Time.IsZero() {
+		cutoff.Time = time.Now().UTC()
+	}
+
+	transactions, err := fetch(ctx, t, chain, address)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := report.ComputeHoldings(transactions, map[string]bool{address: true}, cutoff)
+	balances := &trackerpb.Balances{
+		Address: address,
+		Chain:   chain.Name,
+		AtBlock: int64(cutoff.Block),
+	}
+	if !cutoff.Time.IsZero() {
+		balances.AtTime = timestamppb.New(cutoff.Time)
+	}
+	for _, holding := range snapshot.Holdings {
+		balances.Holdings = append(balances.Holdings, &trackerpb.Holding{
+			Chain:           holding.Chain,
+			ContractAddress: holding.ContractAddress,
+			Symbol:          holding.Symbol,
+			Name:            holding.Name,
+			Decimals:        int32(holding.Decimals),
+			Quantity:        holding.Quantity.String(),
+			Amount:          holding.Amount(),
+		})
+	}
+	if request.IncludeNfts {
+		for _, item := range snapshot.NFTs {
+			balances.Nfts = append(balances.Nfts, &trackerpb.NFT{
+				Chain:           item.Chain,
+				ContractAddress: item.ContractAddress,
+				Symbol:          item.Symbol,
+				Name:            item.Name,
+				TokenId:         item.TokenID,
+			})
+		}
+	}
+	return balances, nil
+}
+
+// tracker validates the address, chain and filter of a request and creates the tracker
+// fetching for it, returning it with the chain and the normalized address
+func (s *GRPCService) tracker(address, chainName string, filter *trackerpb.Filter) (*tracker.Tracker, models.Chain, string, error) {
+	if !processor.New().ValidateEthereumAddress(address) {
+		return nil, models.Chain{}, "", status.Errorf(codes.InvalidArgument, "invalid Ethereum address: %s", address)
+	}
+	chain, err := models.LookupChain(chainName)
+	if err != nil {
+		return nil, models.Chain{}, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	t := s.newTracker(chain)
+	if filter != nil {
+		parsed, err := processor.ParseFilter(processor.FilterSpec{
+			FromDate:     filter.FromDate,
+			ToDate:       filter.ToDate,
+			StartBlock:   int(filter.StartBlock),
+			EndBlock:     int(filter.EndBlock),
+			Types:        filter.Types,
+			Token:        filter.Token,
+			MinValue:     filter.MinValue,
+			Status:       filter.Status,
+			Counterparty: filter.Counterparty,
+		})
+		if err != nil {
+			return nil, models.Chain{}, "", status.Error(codes.InvalidArgument, err.Error())
+		}
+		t.SetFilter(parsed)
+	}
+	return t, chain, strings.ToLower(address), nil
+}
+
+// fetch retrieves the deduplicated history of an address with directions assigned,
+// stopping when the call is cancelled
+func fetch(ctx context.Context, t *tracker.Tracker, chain models.Chain, address string) ([]*models.Transaction, error) {
+	t.SetContext(ctx)
+	var transactions []*models.Transaction
+	err := t.StreamTransactions(address, func(tx *models.Transaction) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		transactions = append(transactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, fetchError(ctx, err)
+	}
+
+	proc := processor.NewForChain(chain)
+	transactions = proc.DeduplicateTransfers(transactions)
+	proc.AssignDirections(transactions, map[string]bool{address: true})
+	proc.SortTransactions(transactions, processor.Ascending)
+	return transactions, nil
+}
+
+// fetchError converts a failed fetch into a gRPC status error
+func fetchError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Unavailable, "failed to fetch transactions: %v", err)
+}
+
+// transactionMessage converts a transaction to its protobuf message
+func transactionMessage(tx *models.Transaction) *trackerpb.Transaction {
+	message := &trackerpb.Transaction{
+		Hash:              tx.Hash,
+		DateTime:          timestamppb.New(tx.DateTime),
+		FromAddress:       tx.FromAddress,
+		ToAddress:         tx.ToAddress,
+		TransactionType:   transactionTypes[tx.TransactionType],
+		AssetContractAddr: tx.AssetContractAddr,
+		AssetSymbol:       tx.AssetSymbol,
+		AssetName:         tx.AssetName,
+		AssetDecimals:     int32(tx.AssetDecimals),
+		TokenId:           tx.TokenID,
+		ValueFormatted:    tx.ValueFormatted,
+		GasFeeEth:         tx.GasFeeETH,
+		BlockNumber:       tx.BlockNumber,
+		TransactionIndex:  tx.TransactionIndex,
+		LogIndex:          tx.LogIndex,
+		Status:            tx.Status,
+		Chain:             tx.Chain,
+		Direction:         directionValues[tx.Direction],
+	}
+	if tx.Value != nil {
+		message.Value = tx.Value.String()
+	}
+	if tx.GasFeeWei != nil {
+		message.GasFeeWei = tx.GasFeeWei.String()
+	}
+	return message
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"crypto-acc-tracking/api/trackerpb"
+	"crypto-acc-tracking/internal/models"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestClient serves the gRPC service on an in-memory listener, with the given stream
+// interceptor if any, and returns a client connected to it
+func newTestClient(t *testing.T, interceptor grpc.StreamServerInterceptor) trackerpb.TrackerClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	var options []grpc.ServerOption
+	if interceptor != nil {
+		options = append(options, grpc.StreamInterceptor(interceptor))
+	}
+	server := grpc.NewServer(options...)
+	trackerpb.RegisterTrackerServer(server, NewGRPCService(replayFactory(fixtureArchive(t))))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return trackerpb.NewTrackerClient(conn)
+}
+
+func TestTrackWalletStreams(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	stream, err := client.TrackWallet(context.Background(), &trackerpb.TrackWalletRequest{Address: testAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received []*trackerpb.Transaction
+	for {
+		tx, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, tx)
+	}
+
+	if len(received) != 6 {
+		t.Fatalf("received %d transactions, want 6", len(received))
+	}
+	for _, tx := range received {
+		if tx.Direction != trackerpb.Direction_DIRECTION_IN || tx.Chain != "ethereum" {
+			t.Errorf("unexpected transaction %v", tx)
+		}
+	}
+
+	// Requests are validated before anything is fetched
+	stream, err = client.TrackWallet(context.Background(), &trackerpb.TrackWalletRequest{Address: "0x123"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid address returned %v", err)
+	}
+}
+
+func TestTrackWalletFilter(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	stream, err := client.TrackWallet(context.Background(), &trackerpb.TrackWalletRequest{
+		Address: testAddress,
+		Filter:  &trackerpb.Filter{Types: "erc20"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TransactionType != trackerpb.TransactionType_TRANSACTION_TYPE_ERC20_TRANSFER || tx.Value != "2500000" {
+		t.Errorf("unexpected transaction %v", tx)
+	}
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("got %v after the only token transfer, want the end of the stream", err)
+	}
+}
+
+func TestTrackWalletCancelled(t *testing.T) {
+	// The server holds back every transaction after the first until the call is cancelled,
+	// and records how its handler ended
+	var (
+		mu      sync.Mutex
+		sent    int
+		handled = make(chan error, 1)
+	)
+	interceptor := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, &holdingStream{ServerStream: ss, sent: func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			sent++
+			return sent
+		}})
+		handled <- err
+		return err
+	}
+	client := newTestClient(t, interceptor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.TrackWallet(ctx, &trackerpb.TrackWalletRequest{Address: testAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("client got %v after cancelling, want Canceled", err)
+	}
+	if err := <-handled; status.Code(err) != codes.Canceled {
+		t.Errorf("handler returned %v, want Canceled", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if sent != 2 {
+		t.Errorf("handler sent %d transactions, want it to stop at the second", sent)
+	}
+}
+
+// holdingStream blocks every send after the first until the call is done
+type holdingStream struct {
+	grpc.ServerStream
+	sent func() int
+}
+
+func (s *holdingStream) SendMsg(m interface{}) error {
+	if s.sent() > 1 {
+		<-s.Context().Done()
+		return s.Context().Err()
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func TestGetSummary(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	summary, err := client.GetSummary(context.Background(), &trackerpb.SummaryRequest{Address: testAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Address != testAddress || summary.Chain != "ethereum" {
+		t.Errorf("summary of %s on %s", summary.Address, summary.Chain)
+	}
+	if summary.TotalTransactions != 6 || summary.UniqueAssets != 2 {
+		t.Errorf("%d transactions of %d assets, want 6 of 2", summary.TotalTransactions, summary.UniqueAssets)
+	}
+	if summary.TransactionTypes[string(models.ETHTransfer)] != 5 || summary.TransactionTypes[string(models.ERC20Transfer)] != 1 {
+		t.Errorf("type counts %v", summary.TransactionTypes)
+	}
+	if summary.Directions[string(models.DirectionIn)] != 6 {
+		t.Errorf("direction counts %v", summary.Directions)
+	}
+	if first, last := summary.FirstTransaction.AsTime().Unix(), summary.LastTransaction.AsTime().Unix(); first != 1700000101 || last != 1700000600 {
+		t.Errorf("first %d and last %d transaction times", first, last)
+	}
+
+	if _, err := client.GetSummary(context.Background(), &trackerpb.SummaryRequest{Address: testAddress, Chain: "nowhere"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown chain returned %v", err)
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	tests := []struct {
+		name    string
+		request *trackerpb.BalancesRequest
+		want    map[string]string // Amount by symbol
+	}{
+		{"now", &trackerpb.BalancesRequest{Address: testAddress}, map[string]string{"ETH": "5", "USDC": "2.5"}},
+		{"at block", &trackerpb.BalancesRequest{Address: testAddress, AtBlock: 103}, map[string]string{"ETH": "3"}},
+		{"at time", &trackerpb.BalancesRequest{Address: testAddress, AtTime: timestamppb.New(timestamppb.Now().AsTime().AddDate(-10, 0, 0))}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		balances, err := client.GetBalances(context.Background(), tt.request)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := make(map[string]string)
+		for _, holding := range balances.Holdings {
+			got[holding.Symbol] = holding.Amount
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: holdings %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for symbol, amount := range tt.want {
+			if got[symbol] != amount {
+				t.Errorf("%s: %s %s, want %s", tt.name, symbol, got[symbol], amount)
+			}
+		}
+	}
+
+	invalid := &trackerpb.BalancesRequest{Address: testAddress, AtTime: &timestamppb.Timestamp{Nanos: -1}}
+	if _, err := client.GetBalances(context.Background(), invalid); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid time returned %v", err)
+	}
+}
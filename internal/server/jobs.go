@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/tracker"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobStatus is the state of a tracking job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+var (
+	// ErrQueueFull is returned when a job is submitted while every queue slot is taken
+	ErrQueueFull = errors.New("job queue is full, try again later")
+	// ErrShutdown is returned when a job is submitted after Shutdown
+	ErrShutdown = errors.New("server is shutting down")
+)
+
+// Job fetches the history of one address into the store
+type Job struct {
+	ID           string     `json:"id"`
+	Address      string     `json:"address"`
+	Chain        string     `json:"chain"`
+	Status       JobStatus  `json:"status"`
+	Transactions int        `json:"transactions"` // Transactions fetched so far
+	Error        string     `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+
+	chain  models.Chain
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Done reports whether the job has finished, successfully or not
+func (j *Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled
+}
+
+// TrackerFactory creates the tracker of a job on a chain, usually one fetching from
+// Etherscan and archiving the responses in the store transactions are served from
+type TrackerFactory func(chain models.Chain) *tracker.Tracker
+
+// Manager runs tracking jobs on a pool of workers. Jobs fetch the complete history of an
+// address with a tracker from the factory.
+type Manager struct {
+	newTracker TrackerFactory
+	queue      chan *Job
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	order  []string
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager starts a pool of workers running jobs from a queue of queueSize jobs
+func NewManager(newTracker TrackerFactory, workers, queueSize int) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		newTracker: newTracker,
+		queue:      make(chan *Job, queueSize),
+		jobs:       make(map[string]*Job),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	return m
+}
+
+// Submit queues a job fetching the history of an address on a chain
+func (m *Manager) Submit(address string, chain models.Chain) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	job := &Job{
+		ID:        id,
+		Address:   strings.ToLower(address),
+		Chain:     chain.Name,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+		chain:     chain,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		cancel()
+		return Job{}, ErrShutdown
+	}
+	select {
+	case m.queue <- job:
+	default:
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	m.jobs[id] = job
+	m.order = append(m.order, id)
+	return *job, nil
+}
+
+// Get returns a snapshot of a job
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// List returns snapshots of all jobs, oldest first
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.order))
+	for _, id := range m.order {
+		jobs = append(jobs, *m.jobs[id])
+	}
+	return jobs
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job has no effect.
+func (m *Manager) Cancel(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	if !job.Done() {
+		job.cancel()
+		if job.Status == JobQueued {
+			m.finish(job, JobCancelled, nil)
+		}
+	}
+	return *job, true
+}
+
+// Shutdown cancels every job and waits for the workers to stop
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	m.closed = true
+	m.cancel()
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// work runs queued jobs until the queue is closed
+func (m *Manager) work() {
+	defer m.wg.Done()
+	for job := range m.queue {
+		m.run(job)
+	}
+}
+
+// run fetches the history of a job's address
+func (m *Manager) run(job *Job) {
+	m.mu.Lock()
+	if job.Done() || job.ctx.Err() != nil {
+		m.finish(job, JobCancelled, nil)
+		m.mu.Unlock()
+		return
+	}
+	started := time.Now().UTC()
+	job.StartedAt = &started
+	job.Status = JobRunning
+	m.mu.Unlock()
+
+	t := m.newTracker(job.chain)
+	t.SetContext(job.ctx)
+
+	// Cancellation takes effect before the next request or transaction
+	err := t.StreamTransactions(job.Address, func(*models.Transaction) error {
+		if err := job.ctx.Err(); err != nil {
+			return err
+		}
+		m.mu.Lock()
+		job.Transactions++
+		m.mu.Unlock()
+		return nil
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case job.ctx.Err() != nil:
+		m.finish(job, JobCancelled, nil)
+	case err != nil:
+		m.finish(job, JobFailed, err)
+	default:
+		m.finish(job, JobSucceeded, nil)
+	}
+}
+
+// finish records the outcome of a job; the caller holds the lock
+func (m *Manager) finish(job *Job, status JobStatus, err error) {
+	if job.Done() {
+		return
+	}
+	finished := time.Now().UTC()
+	job.FinishedAt = &finished
+	job.Status = status
+	if err != nil {
+		job.Error = err.Error()
+	}
+	job.cancel()
+}
+
+// newJobID returns a random job identifier
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
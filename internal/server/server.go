@@ -0,0 +1,345 @@
+package server
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/metrics"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/tracker"
+	"crypto-acc-tracking/internal/version"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 100  // Transactions per page unless limit is given
+	MaxPageSize     = 1000 // Largest accepted limit
+)
+
+// contentTypes maps export formats to the media type they are served with
+var contentTypes = map[exporter.Format]string{
+	exporter.FormatCSV:     "text/csv; charset=utf-8",
+	exporter.FormatJSON:    "application/json",
+	exporter.FormatNDJSON:  "application/x-ndjson",
+	exporter.FormatParquet: "application/vnd.apache.parquet",
+	exporter.FormatSQLite:  "application/vnd.sqlite3",
+	exporter.FormatXLSX:    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Server exposes tracking jobs, their transactions and exports over HTTP:
+//
+//	GET    /healthz                        liveness check
+//	GET    /metrics                        Prometheus metrics
+//	POST   /api/v1/jobs                    start a job: {"address": "0x...", "chain": "ethereum"}
+//	GET    /api/v1/jobs                    list jobs
+//	GET    /api/v1/jobs/{id}               job status
+//	DELETE /api/v1/jobs/{id}               cancel a job
+//	GET    /api/v1/jobs/{id}/transactions  transactions as JSON, filtered and paginated
+//	GET    /api/v1/jobs/{id}/export        download an export in any format
+type Server struct {
+	jobs  *Manager
+	store *archive.Archive
+	mux   *http.ServeMux
+}
+
+// New creates a server for the jobs of a manager, serving transactions from its store
+func New(jobs *Manager, store *archive.Archive) *Server {
+	s := &Server{
+		jobs:  jobs,
+		store: store,
+		mux:   http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.Handle("/metrics", metrics.Handler())
+	s.mux.HandleFunc("/api/v1/jobs", s.handleJobs)
+	s.mux.HandleFunc("/api/v1/jobs/", s.handleJob)
+	return s
+}
+
+// ServeHTTP dispatches a request to its handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleHealth reports that the server is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version.Version})
+}
+
+// handleJobs starts a job or lists all jobs
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": s.jobs.List()})
+	case http.MethodPost:
+		s.submitJob(w, r)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// submitJob queues a job for the address and chain in the request body
+func (s *Server) submitJob(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Address string `json:"address"`
+		Chain   string `json:"chain"`
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if !processor.New().ValidateEthereumAddress(request.Address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid Ethereum address: %s", request.Address))
+		return
+	}
+	chain, err := models.LookupChain(request.Chain)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := s.jobs.Submit(request.Address, chain)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleJob serves a single job and its transactions and exports
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/"), "/")
+
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", id))
+		return
+	}
+
+	switch resource {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, job)
+		case http.MethodDelete:
+			job, _ = s.jobs.Cancel(id)
+			writeJSON(w, http.StatusOK, job)
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+		}
+	case "transactions":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.listTransactions(w, r, job)
+	case "export":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.exportTransactions(w, r, job)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource %s", resource))
+	}
+}
+
+// listTransactions returns one page of the job's transactions
+func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request, job Job) {
+	query := r.URL.Query()
+	page, err := intParam(query, "page", 1)
+	if err != nil || page < 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("page must be a positive number"))
+		return
+	}
+	limit, err := intParam(query, "limit", DefaultPageSize)
+	if err != nil || limit < 1 || limit > MaxPageSize {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", MaxPageSize))
+		return
+	}
+
+	transactions, status, err := s.transactions(job, query)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	total := len(transactions)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"job":          job.ID,
+		"address":      job.Address,
+		"chain":        job.Chain,
+		"total":        total,
+		"page":         page,
+		"limit":        limit,
+		"pages":        (total + limit - 1) / limit,
+		"transactions": transactions[start:end],
+	})
+}
+
+// exportTransactions renders the job's transactions in the requested format and sends
+// the file as a download
+func (s *Server) exportTransactions(w http.ResponseWriter, r *http.Request, job Job) {
+	query := r.URL.Query()
+	format, err := exporter.ParseFormat(query.Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if format == "" {
+		format = exporter.FormatCSV
+	}
+
+	transactions, status, err := s.transactions(job, query)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "crypto-tracker-export-")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to create export directory: %w", err))
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	name := job.Address + exporter.Extension(format)
+	filename := filepath.Join(dir, name)
+	options := exporter.Options{
+		Format:   format,
+		Accounts: exporter.DefaultAccountTemplates(),
+	}
+	if _, err := tracker.Export(transactions, map[string]bool{job.Address: true}, filename, options); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to open export: %w", err))
+		return
+	}
+	defer file.Close()
+
+	contentType, ok := contentTypes[format]
+	if !ok {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, time.Time{}, file)
+}
+
+// transactions reads the transactions of a finished job from the store, filtered and
+// ordered by the query parameters. It returns the HTTP status to report on failure.
+func (s *Server) transactions(job Job, query url.Values) ([]*models.Transaction, int, error) {
+	if job.Status != JobSucceeded {
+		return nil, http.StatusConflict, fmt.Errorf("job %s is %s, transactions are available once it has succeeded", job.ID, job.Status)
+	}
+
+	filter, err := queryFilter(query)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	order, err := processor.ParseSortOrder(query.Get("order"))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	chain, err := models.LookupChain(job.Chain)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	t := tracker.NewForChain("", chain)
+	t.ReplayFrom(s.store)
+	t.SetFilter(filter)
+
+	var transactions []*models.Transaction
+	err = t.StreamTransactions(job.Address, func(tx *models.Transaction) error {
+		transactions = append(transactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Rows are deduplicated by transfer like CLI exports, keeping every leg of a swap
+	proc := processor.NewForChain(chain)
+	transactions = proc.DeduplicateTransfers(transactions)
+	proc.AssignDirections(transactions, map[string]bool{job.Address: true})
+	proc.SortTransactions(transactions, order)
+	return transactions, http.StatusOK, nil
+}
+
+// queryFilter builds a transaction filter from query parameters named like the filter flags
+func queryFilter(query url.Values) (processor.Filter, error) {
+	startBlock, err := intParam(query, "start-block", 0)
+	if err != nil {
+		return processor.Filter{}, fmt.Errorf("invalid start-block: %w", err)
+	}
+	endBlock, err := intParam(query, "end-block", 0)
+	if err != nil {
+		return processor.Filter{}, fmt.Errorf("invalid end-block: %w", err)
+	}
+
+	return processor.ParseFilter(processor.FilterSpec{
+		FromDate:     query.Get("from-date"),
+		ToDate:       query.Get("to-date"),
+		StartBlock:   startBlock,
+		EndBlock:     endBlock,
+		Types:        query.Get("types"),
+		Token:        query.Get("token"),
+		MinValue:     query.Get("min-value"),
+		Status:       query.Get("status"),
+		Counterparty: query.Get("counterparty"),
+	})
+}
+
+// intParam parses an integer query parameter, returning fallback when it is absent
+func intParam(query url.Values, name string, fallback int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// writeJSON sends a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(body)
+}
+
+// writeError sends an error as a JSON response
+func writeError(w http.ResponseWriter, status int, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// methodNotAllowed rejects a request made with an unsupported method
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+}
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/tracker"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAddress = "0x1111111111111111111111111111111111111111"
+	testSender  = "0x2222222222222222222222222222222222222222"
+	testToken   = "0x5555555555555555555555555555555555555555"
+)
+
+// fixtureResponses are the archived Etherscan responses for the test address: five normal
+// transactions and one ERC-20 transfer
+var fixtureResponses = map[string]string{
+	"txlist": `{"status":"1","message":"OK","result":[` + strings.Join([]string{
+		normalTx(105), normalTx(104), normalTx(103), normalTx(102), normalTx(101),
+	}, ",") + `]}`,
+	"tokentx":        `{"status":"1","message":"OK","result":[{"blockNumber":"106","timeStamp":"1700000600","hash":"0x106","from":"` + testSender + `","to":"` + testAddress + `","contractAddress":"` + testToken + `","value":"2500000","tokenName":"USD Coin","tokenSymbol":"USDC","tokenDecimal":"6","logIndex":"3","gasPrice":"1","gasUsed":"1"}]}`,
+	"txlistinternal": noTransactions,
+	"tokennfttx":     noTransactions,
+	"token1155tx":    noTransactions,
+}
+
+const noTransactions = `{"status":"0","message":"No transactions found","result":[]}`
+
+// normalTx returns the JSON of a normal transaction to the test address in a block
+func normalTx(block int) string {
+	return fmt.Sprintf(`{"blockNumber":"%d","timeStamp":"%d","hash":"0x%d","from":"%s","to":"%s","value":"1000000000000000000","gas":"21000","gasPrice":"1000000000","gasUsed":"21000","isError":"0","txreceipt_status":"1","input":"0x"}`,
+		block, 1700000000+block, block, testSender, testAddress)
+}
+
+// fixtureArchive writes the fixture responses for the test address on Ethereum, keyed
+// like a tracker fetching its full history
+func fixtureArchive(t *testing.T) *archive.Archive {
+	t.Helper()
+	store := archive.New(t.TempDir())
+	for action, body := range fixtureResponses {
+		params := url.Values{
+			"chainid":    []string{"1"},
+			"module":     []string{"account"},
+			"action":     []string{action},
+			"address":    []string{testAddress},
+			"startblock": []string{"0"},
+			"endblock":   []string{strconv.Itoa(tracker.LatestBlock)},
+			"page":       []string{"1"},
+			"offset":     []string{strconv.Itoa(tracker.DefaultPageSize)},
+			"sort":       []string{"desc"},
+		}
+		if err := store.Save("https://api.etherscan.io/v2/api", params, []byte(body), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+// replayFactory creates trackers replaying the store instead of calling Etherscan
+func replayFactory(store *archive.Archive) TrackerFactory {
+	return func(chain models.Chain) *tracker.Tracker {
+		t := tracker.NewForChain("", chain)
+		t.ReplayFrom(store)
+		return t
+	}
+}
+
+// newTestServer serves jobs run by the given number of workers on a replayed fixture
+func newTestServer(t *testing.T, workers, queueSize int) (*httptest.Server, *Manager) {
+	t.Helper()
+	store := fixtureArchive(t)
+	jobs := NewManager(replayFactory(store), workers, queueSize)
+	server := httptest.NewServer(New(jobs, store))
+	t.Cleanup(func() {
+		server.Close()
+		jobs.Shutdown()
+	})
+	return server, jobs
+}
+
+// do sends a request and decodes the JSON response into body, if given
+func do(t *testing.T, method, url, payload string, body interface{}) *http.Response {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if body != nil {
+		if err := json.NewDecoder(response.Body).Decode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return response
+}
+
+// submit starts a job for the test address and returns it
+func submit(t *testing.T, server *httptest.Server) Job {
+	t.Helper()
+	var job Job
+	response := do(t, http.MethodPost, server.URL+"/api/v1/jobs", `{"address":"`+testAddress+`"}`, &job)
+	if response.StatusCode != http.StatusAccepted {
+		t.Fatalf("submit returned %d", response.StatusCode)
+	}
+	if location := response.Header.Get("Location"); location != "/api/v1/jobs/"+job.ID {
+		t.Errorf("location %q for job %s", location, job.ID)
+	}
+	return job
+}
+
+// waitFor polls a job until it has finished
+func waitFor(t *testing.T, jobs *Manager, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, _ := jobs.Get(id); job.Done() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestSubmitJob(t *testing.T) {
+	server, jobs := newTestServer(t, 1, 10)
+
+	job := submit(t, server)
+	if job.Address != testAddress || job.Chain != "ethereum" {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	done := waitFor(t, jobs, job.ID)
+	if done.Status != JobSucceeded || done.Transactions != 6 {
+		t.Errorf("job %s with %d transactions (%s), want succeeded with 6", done.Status, done.Transactions, done.Error)
+	}
+
+	var status Job
+	do(t, http.MethodGet, server.URL+"/api/v1/jobs/"+job.ID, "", &status)
+	if status.Status != JobSucceeded {
+		t.Errorf("status %s, want succeeded", status.Status)
+	}
+
+	var list struct{ Jobs []Job }
+	do(t, http.MethodGet, server.URL+"/api/v1/jobs", "", &list)
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != job.ID {
+		t.Errorf("listed %+v", list.Jobs)
+	}
+}
+
+func TestSubmitRejectsInvalidRequests(t *testing.T) {
+	server, _ := newTestServer(t, 1, 10)
+
+	for name, payload := range map[string]string{
+		"invalid address": `{"address":"0x123"}`,
+		"unknown chain":   `{"address":"` + testAddress + `","chain":"nowhere"}`,
+		"unknown field":   `{"address":"` + testAddress + `","wallet":"x"}`,
+		"malformed":       `{`,
+	} {
+		if response := do(t, http.MethodPost, server.URL+"/api/v1/jobs", payload, nil); response.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", name, response.StatusCode)
+		}
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	// Without workers the single queue slot stays taken
+	server, _ := newTestServer(t, 0, 1)
+
+	submit(t, server)
+	var body map[string]string
+	response := do(t, http.MethodPost, server.URL+"/api/v1/jobs", `{"address":"`+testAddress+`"}`, &body)
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503", response.StatusCode)
+	}
+	if body["error"] != ErrQueueFull.Error() {
+		t.Errorf("error %q", body["error"])
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	server, _ := newTestServer(t, 0, 1)
+
+	job := submit(t, server)
+	var cancelled Job
+	response := do(t, http.MethodDelete, server.URL+"/api/v1/jobs/"+job.ID, "", &cancelled)
+	if response.StatusCode != http.StatusOK || cancelled.Status != JobCancelled || cancelled.FinishedAt == nil {
+		t.Errorf("got %d %+v, want the cancelled job", response.StatusCode, cancelled)
+	}
+
+	// Cancelling again leaves the job cancelled
+	do(t, http.MethodDelete, server.URL+"/api/v1/jobs/"+job.ID, "", &cancelled)
+	if cancelled.Status != JobCancelled {
+		t.Errorf("status %s after cancelling twice", cancelled.Status)
+	}
+	if response := do(t, http.MethodDelete, server.URL+"/api/v1/jobs/unknown", "", nil); response.StatusCode != http.StatusNotFound {
+		t.Errorf("cancelling an unknown job returned %d", response.StatusCode)
+	}
+}
+
+func TestShutdownFinishesEveryJob(t *testing.T) {
+	store := fixtureArchive(t)
+	jobs := NewManager(replayFactory(store), 1, 10)
+	for i := 0; i < 10; i++ {
+		if _, err := jobs.Submit(testAddress, models.DefaultChain); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs.Shutdown()
+	for _, job := range jobs.List() {
+		if job.Status != JobSucceeded && job.Status != JobCancelled {
+			t.Errorf("job %s is %s after shutdown", job.ID, job.Status)
+		}
+	}
+	if _, err := jobs.Submit(testAddress, models.DefaultChain); err != ErrShutdown {
+		t.Errorf("submit after shutdown returned %v", err)
+	}
+}
+
+func TestResultsBeforeSuccess(t *testing.T) {
+	server, _ := newTestServer(t, 0, 1)
+	job := submit(t, server)
+
+	for _, resource := range []string{"transactions", "export"} {
+		var body map[string]string
+		response := do(t, http.MethodGet, server.URL+"/api/v1/jobs/"+job.ID+"/"+resource, "", &body)
+		if response.StatusCode != http.StatusConflict {
+			t.Errorf("%s returned %d, want 409", resource, response.StatusCode)
+		}
+		if !strings.Contains(body["error"], "queued") {
+			t.Errorf("%s error %q does not name the status", resource, body["error"])
+		}
+	}
+}
+
+func TestTransactionPages(t *testing.T) {
+	server, jobs := newTestServer(t, 1, 10)
+	job := submit(t, server)
+	waitFor(t, jobs, job.ID)
+	transactionsURL := server.URL + "/api/v1/jobs/" + job.ID + "/transactions"
+
+	type page struct {
+		Total        int
+		Page         int
+		Limit        int
+		Pages        int
+		Transactions []*models.Transaction
+	}
+	tests := []struct {
+		query  string
+		status int
+		want   []string // Block numbers on the page
+		pages  int
+	}{
+		{"", http.StatusOK, []string{"106", "105", "104", "103", "102", "101"}, 1},
+		{"?limit=4", http.StatusOK, []string{"106", "105", "104", "103"}, 2},
+		{"?limit=4&page=2", http.StatusOK, []string{"102", "101"}, 2},
+		{"?limit=4&page=3", http.StatusOK, nil, 2},
+		{"?order=asc&limit=2", http.StatusOK, []string{"101", "102"}, 3},
+		{"?types=erc20", http.StatusOK, []string{"106"}, 1},
+		{"?limit=1000", http.StatusOK, []string{"106", "105", "104", "103", "102", "101"}, 1},
+		{"?page=0", http.StatusBadRequest, nil, 0},
+		{"?page=x", http.StatusBadRequest, nil, 0},
+		{"?limit=0", http.StatusBadRequest, nil, 0},
+		{"?limit=1001", http.StatusBadRequest, nil, 0},
+		{"?order=sideways", http.StatusBadRequest, nil, 0},
+		{"?types=unknown", http.StatusBadRequest, nil, 0},
+	}
+	for _, tt := range tests {
+		var body page
+		response := do(t, http.MethodGet, transactionsURL+tt.query, "", &body)
+		if response.StatusCode != tt.status {
+			t.Errorf("%q returned %d, want %d", tt.query, response.StatusCode, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var blocks []string
+		for _, tx := range body.Transactions {
+			blocks = append(blocks, tx.BlockNumber)
+		}
+		if fmt.Sprint(blocks) != fmt.Sprint(tt.want) || body.Pages != tt.pages {
+			t.Errorf("%q returned blocks %v in %d pages, want %v in %d", tt.query, blocks, body.Pages, tt.want, tt.pages)
+		}
+	}
+}
+
+func TestExportContentTypes(t *testing.T) {
+	server, jobs := newTestServer(t, 1, 10)
+	job := submit(t, server)
+	waitFor(t, jobs, job.ID)
+	exportURL := server.URL + "/api/v1/jobs/" + job.ID + "/export"
+
+	tests := []struct {
+		format      string
+		contentType string
+		extension   string
+	}{
+		{"", "text/csv; charset=utf-8", ".csv"},
+		{"csv", "text/csv; charset=utf-8", ".csv"},
+		{"json", "application/json", ".json"},
+		{"ndjson", "application/x-ndjson", ".ndjson"},
+		{"parquet", "application/vnd.apache.parquet", ".parquet"},
+		{"sqlite", "application/vnd.sqlite3", ".sqlite"},
+		{"xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx"},
+		{"beancount", "text/plain; charset=utf-8", ".beancount"},
+	}
+	for _, tt := range tests {
+		response, err := http.Get(exportURL + "?format=" + tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("%q returned %d: %s", tt.format, response.StatusCode, body)
+			continue
+		}
+		if got := response.Header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%q served as %s, want %s", tt.format, got, tt.contentType)
+		}
+		disposition := fmt.Sprintf("attachment; filename=%q", testAddress+tt.extension)
+		if got := response.Header.Get("Content-Disposition"); got != disposition {
+			t.Errorf("%q disposition %s, want %s", tt.format, got, disposition)
+		}
+		if len(body) == 0 {
+			t.Errorf("%q export is empty", tt.format)
+		}
+	}
+
+	if response := do(t, http.MethodGet, exportURL+"?format=pdf", "", nil); response.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown format returned %d, want 400", response.StatusCode)
+	}
+}
@@ -0,0 +1,227 @@
+package sorter
+
+import (
+	"bufio"
+	"container/heap"
+	"crypto-acc-tracking/internal/models"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultChunkSize is the number of transactions held in memory before a sorted run is
+// spilled to disk
+const DefaultChunkSize = 20000
+
+// Sorter is an external merge sort for transactions. Transactions are buffered up to the
+// chunk size, sorted and written to temporary run files, then merged on iteration, so
+// memory use is bounded by the chunk size rather than the number of transactions.
+type Sorter struct {
+	dir       string
+	chunkSize int
+	less      func(a, b *models.Transaction) bool
+	buffer    []*models.Transaction
+	runs      []string
+	count     int
+}
+
+// New creates a sorter that spills runs to a temporary directory under dir, or under the
+// system temporary directory when dir is empty
+func New(dir string, chunkSize int, less func(a, b *models.Transaction) bool) (*Sorter, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	tempDir, err := os.MkdirTemp(dir, "crypto-tracker-sort-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sort directory: %w", err)
+	}
+
+	return &Sorter{
+		dir:       tempDir,
+		chunkSize: chunkSize,
+		less:      less,
+	}, nil
+}
+
+// Add buffers a transaction, spilling a sorted run to disk when the buffer is full
+func (s *Sorter) Add(tx *models.Transaction) error {
+	s.buffer = append(s.buffer, tx)
+	s.count++
+
+	if len(s.buffer) >= s.chunkSize {
+		return s.spill()
+	}
+	return nil
+}
+
+// Len returns the number of transactions added
+func (s *Sorter) Len() int {
+	return s.count
+}
+
+// Each calls fn for every transaction in sorted order. Transactions that compare equal
+// keep the order in which they were added.
+func (s *Sorter) Each(fn func(tx *models.Transaction) error) error {
+	// Everything fits in memory, no merge needed
+	if len(s.runs) == 0 {
+		sort.SliceStable(s.buffer, func(i, j int) bool {
+			return s.less(s.buffer[i], s.buffer[j])
+		})
+		for _, tx := range s.buffer {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := s.spill(); err != nil {
+		return err
+	}
+
+	return s.merge(fn)
+}
+
+// Close removes the temporary run files
+func (s *Sorter) Close() error {
+	s.buffer = nil
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("failed to remove sort directory: %w", err)
+	}
+	return nil
+}
+
+// spill sorts the buffer and writes it to a new run file
+func (s *Sorter) spill() error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(s.buffer, func(i, j int) bool {
+		return s.less(s.buffer[i], s.buffer[j])
+	})
+
+	filename := filepath.Join(s.dir, fmt.Sprintf("run-%06d.gob", len(s.runs)))
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create sort run: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := gob.NewEncoder(writer)
+	for _, tx := range s.buffer {
+		if err := encoder.Encode(tx); err != nil {
+			return fmt.Errorf("failed to write sort run: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write sort run: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write sort run: %w", err)
+	}
+
+	s.runs = append(s.runs, filename)
+	s.buffer = s.buffer[:0]
+	return nil
+}
+
+// merge performs a k-way merge of the run files
+func (s *Sorter) merge(fn func(tx *models.Transaction) error) error {
+	h := &runHeap{less: s.less}
+
+	for i, filename := range s.runs {
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("failed to open sort run: %w", err)
+		}
+		defer file.Close()
+
+		r := &run{index: i, decoder: gob.NewDecoder(bufio.NewReader(file))}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.runs = append(h.runs, r)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		r := h.runs[0]
+		if err := fn(r.current); err != nil {
+			return err
+		}
+
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	return nil
+}
+
+// run reads transactions back from one sorted run file
+type run struct {
+	index   int
+	decoder *gob.Decoder
+	current *models.Transaction
+}
+
+// next advances to the following transaction, reporting false at the end of the run
+func (r *run) next() (bool, error) {
+	var tx models.Transaction
+	if err := r.decoder.Decode(&tx); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read sort run: %w", err)
+	}
+	r.current = &tx
+	return true, nil
+}
+
+// runHeap orders runs by their current transaction, breaking ties by run index so the
+// merge is stable
+type runHeap struct {
+	runs []*run
+	less func(a, b *models.Transaction) bool
+}
+
+func (h *runHeap) Len() int { return len(h.runs) }
+
+func (h *runHeap) Less(i, j int) bool {
+	a, b := h.runs[i], h.runs[j]
+	if h.less(a.current, b.current) {
+		return true
+	}
+	if h.less(b.current, a.current) {
+		return false
+	}
+	return a.index < b.index
+}
+
+func (h *runHeap) Swap(i, j int) { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+
+func (h *runHeap) Push(x interface{}) { h.runs = append(h.runs, x.(*run)) }
+
+func (h *runHeap) Pop() interface{} {
+	old := h.runs
+	r := old[len(old)-1]
+	h.runs = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,129 @@
+package sorter
+
+import (
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// byBlock orders transactions by block number only, so rows of a block compare equal
+func byBlock(a, b *models.Transaction) bool {
+	x, _ := strconv.Atoi(a.BlockNumber)
+	y, _ := strconv.Atoi(b.BlockNumber)
+	return x < y
+}
+
+func TestMergeIsStableAcrossRuns(t *testing.T) {
+	s, err := New(t.TempDir(), 4, byBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// 30 rows over 3 blocks, added in sequence, so every block is spread across several of
+	// the 8 run files and equal rows must come back in the order they were added
+	for i := 0; i < 30; i++ {
+		tx := &models.Transaction{Hash: strconv.Itoa(i), BlockNumber: strconv.Itoa(2 - i%3)}
+		if err := s.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.runs) < 2 {
+		t.Fatalf("got %d runs, want the rows spilled to several", len(s.runs))
+	}
+
+	var got []*models.Transaction
+	if err := s.Each(func(tx *models.Transaction) error {
+		got = append(got, tx)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 30 {
+		t.Fatalf("got %d rows, want 30", len(got))
+	}
+	previous := map[string]int{}
+	for i, tx := range got {
+		if i > 0 && byBlock(tx, got[i-1]) {
+			t.Fatalf("row %d (block %s) is out of order", i, tx.BlockNumber)
+		}
+		n, _ := strconv.Atoi(tx.Hash)
+		if last, ok := previous[tx.BlockNumber]; ok && n < last {
+			t.Fatalf("row %s of block %s came after row %d", tx.Hash, tx.BlockNumber, last)
+		}
+		previous[tx.BlockNumber] = n
+	}
+}
+
+func TestInMemorySortMatchesMerge(t *testing.T) {
+	transactions := randomTransactions(1000)
+	var orders [2][]string
+	for i, chunkSize := range []int{len(transactions), 64} {
+		s, err := New(t.TempDir(), chunkSize, processor.Ascending.Less)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range transactions {
+			if err := s.Add(tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Each(func(tx *models.Transaction) error {
+			orders[i] = append(orders[i], tx.Hash)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+	}
+
+	for i := range orders[0] {
+		if orders[0][i] != orders[1][i] {
+			t.Fatalf("row %d is %s in memory but %s after merging runs", i, orders[0][i], orders[1][i])
+		}
+	}
+}
+
+// randomTransactions returns n transactions in random order with many sharing a block
+func randomTransactions(n int) []*models.Transaction {
+	r := rand.New(rand.NewSource(1))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]*models.Transaction, n)
+	for i := range transactions {
+		block := r.Intn(n/10 + 1)
+		transactions[i] = &models.Transaction{
+			Hash:             fmt.Sprintf("0x%x", i),
+			DateTime:         start.Add(time.Duration(block) * 12 * time.Second),
+			Chain:            "ethereum",
+			BlockNumber:      strconv.Itoa(block),
+			TransactionIndex: strconv.Itoa(r.Intn(200)),
+			LogIndex:         strconv.Itoa(r.Intn(50)),
+		}
+	}
+	return transactions
+}
+
+func BenchmarkSorter(b *testing.B) {
+	transactions := randomTransactions(100000)
+
+	for i := 0; i < b.N; i++ {
+		s, err := New(b.TempDir(), DefaultChunkSize, processor.Ascending.Less)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, tx := range transactions {
+			if err := s.Add(tx); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := s.Each(func(*models.Transaction) error { return nil }); err != nil {
+			b.Fatal(err)
+		}
+		s.Close()
+	}
+}
@@ -0,0 +1,176 @@
+package tracker
+
+import (
+	"crypto-acc-tracking/internal/archive"
+	"crypto-acc-tracking/internal/etherscan"
+	"crypto-acc-tracking/internal/manifest"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testAddress = "0x1111111111111111111111111111111111111111"
+
+// fakeEtherscan answers Etherscan requests with the body returned by respond and counts them
+type fakeEtherscan struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func newFakeEtherscan(t *testing.T, respond func(params url.Values) string) *fakeEtherscan {
+	f := &fakeEtherscan{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		f.mu.Lock()
+		f.requests = append(f.requests, params)
+		f.mu.Unlock()
+		fmt.Fprint(w, respond(params))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeEtherscan) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// newTestTracker creates a tracker sending its requests to a fake Etherscan without pauses
+func newTestTracker(f *fakeEtherscan) *Tracker {
+	t := New("test-key")
+	t.etherscanClient.SetBaseURL(f.URL)
+	t.SetRateLimiter(etherscan.NewRateLimiter(1000))
+	return t
+}
+
+// normalTx returns the JSON of a normal transaction to the test address
+func normalTx(hash string, block int) string {
+	return fmt.Sprintf(`{"blockNumber":"%d","timeStamp":"1700000000","hash":"%s","from":"0x2222222222222222222222222222222222222222","to":"%s","value":"1000000000000000000","gas":"21000","gasPrice":"1000000000","gasUsed":"21000","isError":"0","txreceipt_status":"1","input":"0x"}`, block, hash, testAddress)
+}
+
+const noTransactions = `{"status":"0","message":"No transactions found","result":[]}`
+
+func streamAll(t *testing.T, tr *Tracker) []*models.Transaction {
+	t.Helper()
+	var txs []*models.Transaction
+	err := tr.StreamTransactions(testAddress, func(tx *models.Transaction) error {
+		txs = append(txs, tx)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txs
+}
+
+func TestArchiveReplaysSyncedRange(t *testing.T) {
+	f := newFakeEtherscan(t, func(params url.Values) string {
+		if params.Get("action") == "txlist" {
+			return `{"status":"1","message":"OK","result":[` + normalTx("0xaaa", 150) + `]}`
+		}
+		return noTransactions
+	})
+	dir := t.TempDir()
+
+	// Sync a block range into the archive
+	online := newTestTracker(f)
+	online.SetFilter(processor.Filter{StartBlock: 100, EndBlock: 200})
+	online.ArchiveTo(archive.New(dir))
+	synced := streamAll(t, online)
+	if len(synced) != 1 {
+		t.Fatalf("synced %d transactions, want 1", len(synced))
+	}
+
+	recorded, err := archive.New(dir).LoadRange(models.DefaultChain.ID, testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded == nil || recorded.StartBlock != 100 || recorded.EndBlock != 200 {
+		t.Fatalf("recorded range %+v, want 100-200", recorded)
+	}
+
+	// Replay it without filters or network calls
+	requests := f.count()
+	offline := newTestTracker(f)
+	offline.ReplayFrom(archive.New(dir))
+	replayed := streamAll(t, offline)
+	if f.count() != requests {
+		t.Errorf("replay sent %d requests", f.count()-requests)
+	}
+	if len(replayed) != 1 || replayed[0].Hash != "0xaaa" {
+		t.Errorf("replayed %v, want the synced transaction", replayed)
+	}
+
+	// Archives without a recorded range are replayed from genesis to the latest block
+	if err := os.Remove(filepath.Join(dir, "1", testAddress, "range.json")); err != nil {
+		t.Fatal(err)
+	}
+	err = offline.StreamTransactions(testAddress, func(*models.Transaction) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "no archived response") {
+		t.Errorf("replay without a range returned %v, want a missing response", err)
+	}
+}
+
+func TestArchiveKeepsWiderRange(t *testing.T) {
+	dir := t.TempDir()
+	a := archive.New(dir)
+	if err := a.SaveRange(1, testAddress, archive.Range{StartBlock: 0, EndBlock: LatestBlock}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveRange(1, testAddress, archive.Range{StartBlock: 100, EndBlock: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := a.LoadRange(1, testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.StartBlock != 0 || recorded.EndBlock != LatestBlock {
+		t.Errorf("recorded range %+v, want the full history", recorded)
+	}
+}
+
+func TestManifestRecordsFilter(t *testing.T) {
+	f := newFakeEtherscan(t, func(params url.Values) string {
+		switch params.Get("action") {
+		case "getblocknobytime":
+			return `{"status":"1","message":"OK","result":"100"}`
+		case "txlist":
+			return `{"status":"1","message":"OK","result":[` + normalTx("0xaaa", 150) + `]}`
+		}
+		return noTransactions
+	})
+
+	filter, err := processor.ParseFilter(processor.FilterSpec{Types: "eth", MinValue: "0.5", FromDate: "2023-01-01", Status: "success"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := newTestTracker(f)
+	tr.SetFilter(filter)
+	outputFile := filepath.Join(t.TempDir(), "wallet.csv")
+	if _, err := tr.ExportWallet(testAddress, outputFile); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := manifest.Load(manifest.PathFor(outputFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := processor.FilterSpec{Types: "eth", MinValue: "0.5", FromDate: "2023-01-01T00:00:00Z", Status: "success"}
+	if m.Options.Filter != want {
+		t.Errorf("manifest filter %+v, want %+v", m.Options.Filter, want)
+	}
+	if m.Rows != 1 {
+		t.Errorf("manifest rows %d, want 1", m.Rows)
+	}
+}
@@ -1,273 +1,859 @@
 package tracker
 
 import (
+	"context"
+	"crypto-acc-tracking/internal/archive"
 	"crypto-acc-tracking/internal/etherscan"
 	"crypto-acc-tracking/internal/exporter"
+	"crypto-acc-tracking/internal/logging"
+	"crypto-acc-tracking/internal/manifest"
+	"crypto-acc-tracking/internal/metrics"
 	"crypto-acc-tracking/internal/models"
 	"crypto-acc-tracking/internal/processor"
+	"crypto-acc-tracking/internal/sorter"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
 const (
-	DefaultPageSize = 10000 // Max transactions per request
-	MaxPages        = 100   // Limit pages to prevent infinite loops
+	DefaultPageSize = 10000    // Max transactions per request
+	MaxPages        = 100      // Limit pages to prevent infinite loops
+	LatestBlock     = 99999999 // End block meaning "up to the latest block"
+
+	progressInterval = 1000 // Rows exported between progress updates
+
+	RateLimitBackoff  = 10 * time.Second // First wait after a rate-limited request, doubled for each further one
+	RateLimitAttempts = 4                // Attempts of a request before a rate limit error is returned
 )
 
 // Tracker represents the main transaction tracking service
 type Tracker struct {
 	etherscanClient *etherscan.Client
 	processor       *processor.Processor
+	exportOptions   exporter.Options
+	filter          processor.Filter
+	chain           models.Chain
+	archive         *archive.Archive
+	ctx             context.Context
 }
 
 // New creates a new tracker instance
 func New(apiKey string) *Tracker {
+	return NewForChain(apiKey, models.DefaultChain)
+}
+
+// NewForChain creates a new tracker instance for the given chain
+func NewForChain(apiKey string, chain models.Chain) *Tracker {
 	return &Tracker{
-		etherscanClient: etherscan.New(apiKey),
-		processor:       processor.New(),
+		etherscanClient: etherscan.NewForChain(apiKey, chain),
+		processor:       processor.NewForChain(chain),
+		chain:           chain,
+		ctx:             context.Background(),
+	}
+}
+
+// SetContext stops fetching once the context is done: pauses and rate limit back-offs
+// end early and no further requests are made, so a run stops between two requests
+func (t *Tracker) SetContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
+// SetExportOptions configures how TrackWallet writes its output
+func (t *Tracker) SetExportOptions(options exporter.Options) {
+	t.exportOptions = options
+}
+
+// SetFilter restricts the transactions the tracker fetches and emits
+func (t *Tracker) SetFilter(filter processor.Filter) {
+	t.filter = filter
+}
+
+// ArchiveTo saves every raw Etherscan response to the archive
+func (t *Tracker) ArchiveTo(a *archive.Archive) {
+	t.archive = a
+	t.etherscanClient.ArchiveTo(a)
+}
+
+// ReplayFrom rebuilds transactions from archived responses without network calls
+func (t *Tracker) ReplayFrom(a *archive.Archive) {
+	t.archive = a
+	t.etherscanClient.ReplayFrom(a)
+}
+
+// SetRateLimiter paces API calls with the limiter instead of fixed pauses
+func (t *Tracker) SetRateLimiter(limiter *etherscan.RateLimiter) {
+	t.etherscanClient.SetRateLimiter(limiter)
+}
+
+// SetKeyPool spreads API calls over the keys of the pool instead of fixed pauses
+func (t *Tracker) SetKeyPool(pool *etherscan.KeyPool) {
+	t.etherscanClient.SetKeyPool(pool)
+}
+
+// pause waits between API calls to respect rate limits, unless replaying from an archive
+// or paced by a rate limiter. It returns early when the context is done.
+func (t *Tracker) pause(d time.Duration) {
+	if !t.etherscanClient.Offline() && !t.etherscanClient.Throttled() {
+		t.sleep(d)
 	}
 }
 
-// TrackWallet retrieves and exports all transactions for a wallet address
+// sleep waits for the duration or until the context is done
+func (t *Tracker) sleep(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-t.ctx.Done():
+	}
+}
+
+// TrackWallet retrieves and exports all transactions for a wallet address. Pages are
+// processed as they arrive and spilled to an on-disk sort, so memory use stays bounded
+// regardless of the wallet size.
 func (t *Tracker) TrackWallet(address, outputFile string) error {
+	summary, err := t.ExportWallet(address, outputFile)
+	if err != nil {
+		return err
+	}
+
+	LogSummary(summary)
+
+	slog.Info("Export completed successfully")
+	return nil
+}
+
+// ExportWallet retrieves and exports all transactions for a wallet address like
+// TrackWallet, and returns the export summary instead of logging it
+func (t *Tracker) ExportWallet(address, outputFile string) (map[string]interface{}, error) {
 	// Validate address
 	if !t.processor.ValidateEthereumAddress(address) {
-		return fmt.Errorf("invalid Ethereum address: %s", address)
+		return nil, fmt.Errorf("invalid Ethereum address: %s", address)
 	}
 
 	// Normalize address to lowercase
 	address = strings.ToLower(address)
 
-	fmt.Printf("🔍 Tracking wallet: %s\n", address)
-	fmt.Printf("📊 Fetching transaction data...\n\n")
-
-	// Fetch all transaction types
-	var allTransactions []*models.Transaction
+	slog.Info("Tracking wallet", "address", address)
 
-	// 1. Fetch normal transactions
-	fmt.Printf("⏳ Fetching normal transactions...\n")
-	normalTxs, err := t.fetchAllNormalTransactions(address)
+	s, err := sorter.New("", sorter.DefaultChunkSize, t.exportOptions.Order.Less)
 	if err != nil {
-		return fmt.Errorf("failed to fetch normal transactions: %w", err)
+		return nil, err
 	}
-	fmt.Printf("✅ Found %d normal transactions\n", len(normalTxs))
+	defer s.Close()
 
-	// 2. Fetch internal transactions
-	fmt.Printf("⏳ Fetching internal transactions...\n")
-	internalTxs, err := t.fetchAllInternalTransactions(address)
+	if err := t.StreamTransactions(address, s.Add); err != nil {
+		return nil, err
+	}
+
+	options := t.exportOptions
+	options.Filter = t.filter.Spec()
+	return ExportSorted(s, map[string]bool{address: true}, outputFile, options)
+}
+
+// Sync fetches the history of a wallet without exporting it, for trackers that archive
+// their responses, and returns the number of transactions fetched
+func (t *Tracker) Sync(address string) (int, error) {
+	count := 0
+	err := t.StreamTransactions(address, func(*models.Transaction) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// CurrentBlock returns the number of the latest block on the tracker's chain
+func (t *Tracker) CurrentBlock() (int, error) {
+	return t.blockAt(time.Now())
+}
+
+// StreamBlocks emits the transactions of an address within a block range, further limited
+// by the tracker's filter. Together with CurrentBlock it lets a tracker serve as a watch source.
+func (t *Tracker) StreamBlocks(address string, startBlock, endBlock int, emit func(*models.Transaction) error) error {
+	filter := t.filter
+	defer t.SetFilter(filter)
+
+	window := filter
+	window.StartBlock = max(filter.StartBlock, startBlock)
+	window.EndBlock = endBlock
+	if filter.EndBlock > 0 {
+		window.EndBlock = min(filter.EndBlock, endBlock)
+	}
+	if window.StartBlock > window.EndBlock {
+		return nil
+	}
+	t.SetFilter(window)
+
+	return t.StreamTransactions(address, emit)
+}
+
+// Export writes transactions of the owned addresses in the configured format and returns the summary
+func Export(transactions []*models.Transaction, owned map[string]bool, outputFile string, options exporter.Options) (map[string]interface{}, error) {
+	options.Naming.Companions = []string{manifest.Suffix}
+	outputFile, err := options.Naming.Resolve(outputFile, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to fetch internal transactions: %w", err)
+		return nil, err
 	}
-	fmt.Printf("✅ Found %d internal transactions\n", len(internalTxs))
 
-	// Wait before next API call batch
-	time.Sleep(2 * time.Second)
+	if options.Format == "" {
+		options.Format = exporter.InferFormat(outputFile)
+	}
+	options.Owned = owned
 
-	// 3. Fetch token transactions
-	fmt.Printf("⏳ Fetching ERC-20 token transactions...\n")
-	tokenTxs, err := t.fetchAllTokenTransactions(address)
+	exp, err := exporter.New(outputFile, options)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Failed to fetch token transactions: %v\n", err)
-		fmt.Printf("📄 Continuing with available data...\n")
-		tokenTxs = []*models.Transaction{} // Use empty slice
-	} else {
-		fmt.Printf("✅ Found %d token transactions\n", len(tokenTxs))
+		return nil, err
 	}
 
-	// Wait before next API call batch
-	time.Sleep(2 * time.Second)
+	slog.Info("Exporting", "format", options.Format, "file", outputFile)
+	if err := exp.Export(transactions); err != nil {
+		return nil, fmt.Errorf("failed to export to %s: %w", options.Format, err)
+	}
 
-	// 4. Fetch NFT transactions
-	fmt.Printf("⏳ Fetching ERC-721 NFT transactions...\n")
-	nftTxs, err := t.fetchAllNFTTransactions(address)
+	m := manifest.New(owned, options)
+	for _, tx := range transactions {
+		m.Add(tx)
+	}
+	if err := writeManifest(m, outputFile); err != nil {
+		return nil, err
+	}
+
+	return exp.GetExportSummary(transactions), nil
+}
+
+// ExportSorted streams the sorted transactions of the owned addresses into the configured
+// format, dropping duplicate transfers and assigning directions on the way, and returns the summary
+func ExportSorted(s *sorter.Sorter, owned map[string]bool, outputFile string, options exporter.Options) (map[string]interface{}, error) {
+	if options.SplitBy != exporter.PartitionNone {
+		return exportPartitioned(s, owned, outputFile, options)
+	}
+
+	options.Naming.Companions = []string{manifest.Suffix}
+	outputFile, err := options.Naming.Resolve(outputFile, time.Now())
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Failed to fetch NFT transactions: %v\n", err)
-		fmt.Printf("📄 Continuing with available data...\n")
-		nftTxs = []*models.Transaction{} // Use empty slice
-	} else {
-		fmt.Printf("✅ Found %d NFT transactions\n", len(nftTxs))
+		return nil, err
 	}
 
-	// Combine all transactions
-	allTransactions = append(allTransactions, normalTxs...)
-	allTransactions = append(allTransactions, internalTxs...)
-	allTransactions = append(allTransactions, tokenTxs...)
-	allTransactions = append(allTransactions, nftTxs...)
+	if options.Format == "" {
+		options.Format = exporter.InferFormat(outputFile)
+	}
+	options.Owned = owned
 
-	// Deduplicate and sort
-	fmt.Printf("\n📋 Processing transactions...\n")
-	allTransactions = t.processor.DeduplicateTransactions(allTransactions)
-	t.processor.SortTransactionsByTime(allTransactions)
+	exp, err := exporter.NewStream(outputFile, options)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("✅ Total unique transactions: %d\n", len(allTransactions))
+	slog.Info("Exporting", "format", options.Format, "file", outputFile)
+	if err := exp.Open(); err != nil {
+		return nil, fmt.Errorf("failed to export to %s: %w", options.Format, err)
+	}
 
-	// Export to CSV
-	fmt.Printf("\n💾 Exporting to CSV: %s\n", outputFile)
-	csvExporter := exporter.NewCSVExporter(outputFile)
-	if err := csvExporter.Export(allTransactions); err != nil {
-		return fmt.Errorf("failed to export to CSV: %w", err)
+	proc := processor.New()
+	deduplicator := processor.NewBlockDeduplicator()
+	m := manifest.New(owned, options)
+	stage := "exporting " + filepath.Base(outputFile)
+	written := 0
+	err = s.Each(func(tx *models.Transaction) error {
+		if deduplicator.Duplicate(tx) {
+			return nil
+		}
+		proc.AssignDirection(tx, owned)
+		if err := exp.Write(tx); err != nil {
+			return err
+		}
+		m.Add(tx)
+		if written++; written%progressInterval == 0 {
+			logging.Update(stage, written, s.Len())
+		}
+		return nil
+	})
+	logging.Finish(stage, written)
+	if err != nil {
+		exp.Abort()
+		return nil, fmt.Errorf("failed to export to %s: %w", options.Format, err)
 	}
 
-	// Print summary
-	summary := csvExporter.GetExportSummary(allTransactions)
-	t.printSummary(summary)
+	if err := exp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to export to %s: %w", options.Format, err)
+	}
 
-	fmt.Printf("\n🎉 Export completed successfully!\n")
-	return nil
+	if err := writeManifest(m, outputFile); err != nil {
+		return nil, err
+	}
+
+	return exp.Summary(), nil
 }
 
-// fetchAllNormalTransactions fetches all normal transactions with pagination
-func (t *Tracker) fetchAllNormalTransactions(address string) ([]*models.Transaction, error) {
-	var allTransactions []*models.Transaction
-	page := 1
+// exportPartitioned writes one file per partition of the sorted transactions. Rows are
+// re-sorted on disk by partition first, so only one partition file is open at a time.
+func exportPartitioned(s *sorter.Sorter, owned map[string]bool, outputFile string, options exporter.Options) (map[string]interface{}, error) {
+	if options.Format == "" {
+		options.Format = exporter.InferFormat(outputFile)
+	}
+	options.Owned = owned
+	options.Naming.Companions = []string{manifest.Suffix}
 
-	for page <= MaxPages {
-		txs, err := t.etherscanClient.GetNormalTransactions(address, 0, 99999999, page, DefaultPageSize)
-		if err != nil {
-			return nil, err
+	partitioned, err := sorter.New("", sorter.DefaultChunkSize, func(a, b *models.Transaction) bool {
+		if keyA, keyB := options.PartitionKey(a), options.PartitionKey(b); keyA != keyB {
+			return keyA < keyB
 		}
+		return options.Order.Less(a, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer partitioned.Close()
 
-		if len(txs) == 0 {
-			break
+	proc := processor.New()
+	deduplicator := processor.NewBlockDeduplicator()
+	err = s.Each(func(tx *models.Transaction) error {
+		if deduplicator.Duplicate(tx) {
+			return nil
+		}
+		proc.AssignDirection(tx, owned)
+		return partitioned.Add(tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to split export by %s: %w", options.SplitBy, err)
+	}
+
+	slog.Info("Exporting", "format", options.Format, "split_by", options.SplitBy, "files", options.PartitionFile(outputFile, "*"))
+
+	now := time.Now()
+	total := exporter.NewSummaryCounter()
+	partitions := 0
+
+	var (
+		key      string
+		filename string
+		exp      exporter.StreamExporter
+		m        *manifest.Manifest
+	)
+
+	// finish completes the open partition and reports its own summary
+	finish := func() error {
+		done := exp
+		exp = nil
+		if err := done.Close(); err != nil {
+			return fmt.Errorf("failed to export to %s: %w", options.Format, err)
+		}
+		if err := writeManifest(m, filename); err != nil {
+			return err
 		}
 
-		for _, tx := range txs {
-			processedTx, err := t.processor.ProcessNormalTransaction(tx)
+		summary := done.Summary()
+		slog.Info("Partition exported", "partition", key, "file", filename, "transactions", summary["total_transactions"], "assets", summary["unique_assets"])
+		partitions++
+		return nil
+	}
+
+	err = partitioned.Each(func(tx *models.Transaction) error {
+		if next := options.PartitionKey(tx); exp == nil || next != key {
+			if exp != nil {
+				if err := finish(); err != nil {
+					return err
+				}
+			}
+
+			key = next
+			name, err := options.Naming.Resolve(options.PartitionFile(outputFile, key), now)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+
+			filename = name
+			exp, err = exporter.NewStream(filename, options)
 			if err != nil {
-				fmt.Printf("⚠️  Warning: Failed to process normal transaction %s: %v\n", tx.Hash, err)
-				continue
+				return err
+			}
+			if err := exp.Open(); err != nil {
+				exp = nil
+				return fmt.Errorf("failed to export to %s: %w", options.Format, err)
 			}
-			allTransactions = append(allTransactions, processedTx)
+			m = manifest.New(owned, options)
 		}
 
-		if len(txs) < DefaultPageSize {
-			break
+		if err := exp.Write(tx); err != nil {
+			return fmt.Errorf("failed to export to %s: %w", options.Format, err)
 		}
+		m.Add(tx)
+		total.Add(tx)
+		return nil
+	})
+	if err != nil {
+		if exp != nil {
+			exp.Abort()
+		}
+		return nil, err
+	}
 
-		page++
-		time.Sleep(3 * time.Second) // Rate limiting
+	if exp != nil {
+		if err := finish(); err != nil {
+			return nil, err
+		}
+	}
+	if partitions == 0 {
+		slog.Warn("No transactions to export, no files written")
 	}
 
-	return allTransactions, nil
+	summary := total.Summary(options.PartitionFile(outputFile, "*"))
+	summary["partitions"] = partitions
+	return summary, nil
 }
 
-// fetchAllInternalTransactions fetches all internal transactions with pagination
-func (t *Tracker) fetchAllInternalTransactions(address string) ([]*models.Transaction, error) {
+// writeManifest hashes the output file and writes its manifest next to it
+func writeManifest(m *manifest.Manifest, outputFile string) error {
+	if err := m.AddFile(outputFile, m.Rows); err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+
+	manifestFile := manifest.PathFor(outputFile)
+	if err := m.Write(manifestFile); err != nil {
+		return err
+	}
+
+	slog.Info("Manifest written", "file", manifestFile)
+	return nil
+}
+
+// FetchTransactions retrieves, deduplicates and sorts all transactions for a wallet address
+func (t *Tracker) FetchTransactions(address string) ([]*models.Transaction, error) {
 	var allTransactions []*models.Transaction
-	page := 1
+	err := t.StreamTransactions(address, func(tx *models.Transaction) error {
+		allTransactions = append(allTransactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduplicate by transfer, so both legs of a swap sharing a hash are kept, and sort
+	allTransactions = t.processor.DeduplicateTransfers(allTransactions)
+	t.processor.SortTransactions(allTransactions, processor.Descending)
+
+	slog.Info("Processed transactions", "address", strings.ToLower(address), "unique", len(allTransactions))
+
+	return allTransactions, nil
+}
 
-	for page <= MaxPages {
-		txs, err := t.etherscanClient.GetInternalTransactions(address, 0, 99999999, page, DefaultPageSize)
+// StreamTransactions retrieves all transactions for a wallet address and emits each one
+// as soon as its block range has been fetched. An invalid API key or an exhausted rate limit
+// stops the run; other failures to fetch token and NFT transfers are only logged.
+func (t *Tracker) StreamTransactions(address string, emit func(*models.Transaction) error) error {
+	if !t.processor.ValidateEthereumAddress(address) {
+		return fmt.Errorf("invalid Ethereum address: %s", address)
+	}
+	address = strings.ToLower(address)
+
+	startBlock, endBlock, err := t.blockRange(address)
+	if err != nil {
+		return err
+	}
+
+	if startBlock > 0 || endBlock < LatestBlock {
+		slog.Info("Fetching transactions", "address", address, "start_block", startBlock, "end_block", endBlock)
+	} else {
+		slog.Info("Fetching transactions", "address", address)
+	}
+
+	// Failures of the consumer are fatal even where fetch failures are only warnings
+	var emitErr error
+	emit = recordError(t.filter.Apply(emit), &emitErr)
+
+	// 1. Fetch normal transactions
+	if t.filter.Wants(models.ETHTransfer, models.ContractCall) {
+		count, err := t.streamNormalTransactions(address, startBlock, endBlock, emit)
+		logging.Finish(progressStage(address, "normal"), count)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to fetch normal transactions: %w", err)
 		}
+		slog.Info("Fetched normal transactions", "address", address, "count", count)
+	}
 
-		if len(txs) == 0 {
-			break
+	// 2. Fetch internal transactions
+	if t.filter.Wants(models.InternalTx) {
+		count, err := t.streamInternalTransactions(address, startBlock, endBlock, emit)
+		logging.Finish(progressStage(address, "internal"), count)
+		if err != nil {
+			return fmt.Errorf("failed to fetch internal transactions: %w", err)
 		}
+		slog.Info("Fetched internal transactions", "address", address, "count", count)
 
-		for _, tx := range txs {
-			processedTx, err := t.processor.ProcessInternalTransaction(tx)
-			if err != nil {
-				fmt.Printf("⚠️  Warning: Failed to process internal transaction %s: %v\n", tx.Hash, err)
-				continue
-			}
-			allTransactions = append(allTransactions, processedTx)
+		// Wait before next API call batch
+		t.pause(2 * time.Second)
+	}
+
+	// 3. Fetch token transactions
+	if t.filter.Wants(models.ERC20Transfer) {
+		count, err := t.streamTokenTransactions(address, startBlock, endBlock, emit)
+		logging.Finish(progressStage(address, "token"), count)
+		if emitErr != nil {
+			return emitErr
+		}
+		if fatal(err) {
+			return fmt.Errorf("failed to fetch ERC-20 token transactions: %w", err)
+		}
+		if err != nil {
+			slog.Warn("Failed to fetch ERC-20 token transactions, continuing with available data", "address", address, "count", count, "error", err)
+		} else {
+			slog.Info("Fetched ERC-20 token transactions", "address", address, "count", count)
 		}
 
-		if len(txs) < DefaultPageSize {
-			break
+		// Wait before next API call batch
+		t.pause(2 * time.Second)
+	}
+
+	// 4. Fetch NFT transactions
+	if t.filter.Wants(models.ERC721Transfer) {
+		count, err := t.streamNFTTransactions(address, startBlock, endBlock, emit)
+		logging.Finish(progressStage(address, "nft"), count)
+		if emitErr != nil {
+			return emitErr
+		}
+		if fatal(err) {
+			return fmt.Errorf("failed to fetch ERC-721 NFT transactions: %w", err)
+		}
+		if err != nil {
+			slog.Warn("Failed to fetch ERC-721 NFT transactions, continuing with available data", "address", address, "count", count, "error", err)
+		} else {
+			slog.Info("Fetched ERC-721 NFT transactions", "address", address, "count", count)
 		}
 
-		page++
-		time.Sleep(3 * time.Second) // Rate limiting
+		// Wait before next API call batch
+		t.pause(2 * time.Second)
 	}
 
-	return allTransactions, nil
-}
+	// 5. Fetch ERC-1155 multi-token transactions
+	if t.filter.Wants(models.ERC1155Transfer) {
+		count, err := t.streamERC1155Transactions(address, startBlock, endBlock, emit)
+		logging.Finish(progressStage(address, "erc1155"), count)
+		if emitErr != nil {
+			return emitErr
+		}
+		if fatal(err) {
+			return fmt.Errorf("failed to fetch ERC-1155 transactions: %w", err)
+		}
+		if err != nil {
+			slog.Warn("Failed to fetch ERC-1155 transactions, continuing with available data", "address", address, "count", count, "error", err)
+		} else {
+			slog.Info("Fetched ERC-1155 transactions", "address", address, "count", count)
+		}
+	}
 
-// fetchAllTokenTransactions fetches all token transactions with pagination
-func (t *Tracker) fetchAllTokenTransactions(address string) ([]*models.Transaction, error) {
-	var allTransactions []*models.Transaction
-	page := 1
+	// Record what was archived so replays request the same range
+	if t.archive != nil && !t.etherscanClient.Offline() {
+		synced := archive.Range{StartBlock: startBlock, EndBlock: endBlock, SyncedAt: time.Now()}
+		if err := t.archive.SaveRange(t.chain.ID, address, synced); err != nil {
+			return err
+		}
+	}
 
-	for page <= MaxPages {
-		txs, err := t.etherscanClient.GetTokenTransactions(address, 0, 99999999, page, DefaultPageSize)
+	return nil
+}
+
+// blockRange returns the blocks to fetch, narrowing the filter's block range by its dates.
+// Dates resolve to the last block before them, so the range may start slightly early;
+// the filter drops those transactions by time. Replays read the full archived history,
+// requesting the range it was synced for, and leave all filtering to the filter.
+func (t *Tracker) blockRange(address string) (int, int, error) {
+	if t.etherscanClient.Offline() {
+		synced, err := t.archive.LoadRange(t.chain.ID, address)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
+		}
+		if synced == nil {
+			return 0, LatestBlock, nil
 		}
+		return synced.StartBlock, synced.EndBlock, nil
+	}
 
-		if len(txs) == 0 {
-			break
+	startBlock, endBlock := t.filter.StartBlock, t.filter.EndBlock
+	if endBlock == 0 {
+		endBlock = LatestBlock
+	}
+
+	if !t.filter.FromTime.IsZero() {
+		block, err := t.blockAt(t.filter.FromTime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to resolve start date to a block: %w", err)
 		}
+		if block > startBlock {
+			startBlock = block
+		}
+	}
 
-		for _, tx := range txs {
-			processedTx, err := t.processor.ProcessTokenTransaction(tx)
-			if err != nil {
-				fmt.Printf("⚠️  Warning: Failed to process token transaction %s: %v\n", tx.Hash, err)
-				continue
-			}
-			allTransactions = append(allTransactions, processedTx)
+	if !t.filter.ToTime.IsZero() && t.filter.ToTime.Before(time.Now()) {
+		block, err := t.blockAt(t.filter.ToTime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to resolve end date to a block: %w", err)
 		}
+		if block < endBlock {
+			endBlock = block
+		}
+	}
 
-		if len(txs) < DefaultPageSize {
-			break
+	if startBlock > endBlock {
+		return 0, 0, fmt.Errorf("start block %d is after end block %d", startBlock, endBlock)
+	}
+	return startBlock, endBlock, nil
+}
+
+// blockAt returns the last block mined at or before a time
+func (t *Tracker) blockAt(at time.Time) (int, error) {
+	var block int
+	err := t.call(func() error {
+		var err error
+		block, err = t.etherscanClient.GetBlockNumberByTime(at.Unix())
+		return err
+	})
+	return block, err
+}
+
+// tokenContract returns the token contract the API should restrict token streams to
+func (t *Tracker) tokenContract() string {
+	if t.etherscanClient.Offline() {
+		return ""
+	}
+	return t.filter.Token
+}
+
+// recordError wraps an emit function so its errors are kept in *target
+func recordError(emit func(*models.Transaction) error, target *error) func(*models.Transaction) error {
+	return func(tx *models.Transaction) error {
+		if err := emit(tx); err != nil {
+			*target = err
+			return err
 		}
+		return nil
+	}
+}
 
-		page++
-		time.Sleep(3 * time.Second) // Rate limiting
+// source describes how one kind of Etherscan result is fetched and processed
+type source[T any] struct {
+	name    string
+	fetch   func(startBlock, endBlock, page int) ([]T, error)
+	process func(T) (*models.Transaction, error)
+	hash    func(T) string
+	count   int // Transactions emitted so far
+}
+
+// streamNormalTransactions fetches all normal transactions, emitting them as they arrive
+func (t *Tracker) streamNormalTransactions(address string, startBlock, endBlock int, emit func(*models.Transaction) error) (int, error) {
+	s := &source[models.EtherscanNormalTx]{
+		name: "normal",
+		fetch: func(startBlock, endBlock, page int) ([]models.EtherscanNormalTx, error) {
+			return t.etherscanClient.GetNormalTransactions(address, startBlock, endBlock, page, DefaultPageSize)
+		},
+		process: t.processor.ProcessNormalTransaction,
+		hash:    func(tx models.EtherscanNormalTx) string { return tx.Hash },
 	}
+	err := streamRange(t, s, address, startBlock, endBlock, emit)
+	return s.count, err
+}
 
-	return allTransactions, nil
+// streamInternalTransactions fetches all internal transactions, emitting them as they arrive
+func (t *Tracker) streamInternalTransactions(address string, startBlock, endBlock int, emit func(*models.Transaction) error) (int, error) {
+	s := &source[models.EtherscanInternalTx]{
+		name: "internal",
+		fetch: func(startBlock, endBlock, page int) ([]models.EtherscanInternalTx, error) {
+			return t.etherscanClient.GetInternalTransactions(address, startBlock, endBlock, page, DefaultPageSize)
+		},
+		process: t.processor.ProcessInternalTransaction,
+		hash:    func(tx models.EtherscanInternalTx) string { return tx.Hash },
+	}
+	err := streamRange(t, s, address, startBlock, endBlock, emit)
+	return s.count, err
 }
 
-// fetchAllNFTTransactions fetches all NFT transactions with pagination
-func (t *Tracker) fetchAllNFTTransactions(address string) ([]*models.Transaction, error) {
-	var allTransactions []*models.Transaction
-	page := 1
+// streamTokenTransactions fetches all token transactions, emitting them as they arrive
+func (t *Tracker) streamTokenTransactions(address string, startBlock, endBlock int, emit func(*models.Transaction) error) (int, error) {
+	s := &source[models.EtherscanTokenTx]{
+		name: "token",
+		fetch: func(startBlock, endBlock, page int) ([]models.EtherscanTokenTx, error) {
+			return t.etherscanClient.GetTokenTransactions(address, t.tokenContract(), startBlock, endBlock, page, DefaultPageSize)
+		},
+		process: t.processor.ProcessTokenTransaction,
+		hash:    func(tx models.EtherscanTokenTx) string { return tx.Hash },
+	}
+	err := streamRange(t, s, address, startBlock, endBlock, emit)
+	return s.count, err
+}
 
-	for page <= MaxPages {
-		txs, err := t.etherscanClient.GetNFTTransactions(address, 0, 99999999, page, DefaultPageSize)
-		if err != nil {
-			return nil, err
+// streamNFTTransactions fetches all NFT transactions, emitting them as they arrive
+func (t *Tracker) streamNFTTransactions(address string, startBlock, endBlock int, emit func(*models.Transaction) error) (int, error) {
+	s := &source[models.EtherscanNFTTx]{
+		name: "nft",
+		fetch: func(startBlock, endBlock, page int) ([]models.EtherscanNFTTx, error) {
+			return t.etherscanClient.GetNFTTransactions(address, t.tokenContract(), startBlock, endBlock, page, DefaultPageSize)
+		},
+		process: t.processor.ProcessNFTTransaction,
+		hash:    func(tx models.EtherscanNFTTx) string { return tx.Hash },
+	}
+	err := streamRange(t, s, address, startBlock, endBlock, emit)
+	return s.count, err
+}
+
+// streamERC1155Transactions fetches all ERC-1155 transactions, emitting them as they arrive
+func (t *Tracker) streamERC1155Transactions(address string, startBlock, endBlock int, emit func(*models.Transaction) error) (int, error) {
+	s := &source[models.EtherscanERC1155Tx]{
+		name: "erc1155",
+		fetch: func(startBlock, endBlock, page int) ([]models.EtherscanERC1155Tx, error) {
+			return t.etherscanClient.GetERC1155Transactions(address, t.tokenContract(), startBlock, endBlock, page, DefaultPageSize)
+		},
+		process: t.processor.ProcessERC1155Transaction,
+		hash:    func(tx models.EtherscanERC1155Tx) string { return tx.Hash },
+	}
+	err := streamRange(t, s, address, startBlock, endBlock, emit)
+	return s.count, err
+}
+
+// streamRange fetches the results of a source in a block range page by page and emits them
+// once the range is complete. Etherscan serves at most 10,000 results per query, so when
+// paging runs into that window the range is split in half and each half fetched on its own,
+// newer blocks first like the pages. Results of a range are only held until it is known not
+// to need splitting, which bounds memory use by the window.
+func streamRange[T any](t *Tracker, s *source[T], address string, startBlock, endBlock int, emit func(*models.Transaction) error) error {
+	var results []T
+	for page := 1; page <= MaxPages; page++ {
+		if page > 1 {
+			t.pause(3 * time.Second) // Rate limiting
+		}
+		if err := t.ctx.Err(); err != nil {
+			return err
 		}
 
-		if len(txs) == 0 {
+		var txs []T
+		err := t.call(func() error {
+			var err error
+			txs, err = s.fetch(startBlock, endBlock, page)
+			return err
+		})
+		if errors.Is(err, etherscan.ErrNoResults) {
 			break
 		}
-
-		for _, tx := range txs {
-			processedTx, err := t.processor.ProcessNFTTransaction(tx)
-			if err != nil {
-				fmt.Printf("⚠️  Warning: Failed to process NFT transaction %s: %v\n", tx.Hash, err)
-				continue
+		if errors.Is(err, etherscan.ErrResultWindowTooLarge) && startBlock < endBlock {
+			mid := startBlock + (endBlock-startBlock)/2
+			slog.Debug("Result window too large, splitting block range", "address", address, "source", s.name, "start_block", startBlock, "end_block", endBlock)
+			t.pause(3 * time.Second)
+			if err := streamRange(t, s, address, mid+1, endBlock, emit); err != nil {
+				return err
 			}
-			allTransactions = append(allTransactions, processedTx)
+			t.pause(3 * time.Second)
+			if err := t.ctx.Err(); err != nil {
+				return err
+			}
+			return streamRange(t, s, address, startBlock, mid, emit)
+		}
+		if err != nil {
+			return err
 		}
 
+		results = append(results, txs...)
+		slog.Debug("Fetched page", "address", address, "source", s.name, "page", page, "results", len(txs))
 		if len(txs) < DefaultPageSize {
 			break
 		}
+	}
 
-		page++
-		time.Sleep(3 * time.Second) // Rate limiting
+	for _, tx := range results {
+		processedTx, err := s.process(tx)
+		if err != nil {
+			metrics.ProcessingErrors.WithLabelValues(s.name).Inc()
+			slog.Warn("Failed to process transaction", "source", s.name, "hash", s.hash(tx), "error", err)
+			continue
+		}
+		metrics.TransactionsProcessed.WithLabelValues(s.name).Inc()
+		if err := emit(processedTx); err != nil {
+			return err
+		}
+		s.count++
 	}
+	logging.Update(progressStage(address, s.name), s.count, 0)
 
-	return allTransactions, nil
+	return nil
 }
 
-// printSummary prints a summary of the export operation
-func (t *Tracker) printSummary(summary map[string]interface{}) {
-	fmt.Printf("\n📈 Export Summary:\n")
-	fmt.Printf("   Total Transactions: %d\n", summary["total_transactions"])
-	fmt.Printf("   Unique Assets: %d\n", summary["unique_assets"])
-	fmt.Printf("   Output File: %s\n", summary["filename"])
+// call runs an API call, backing off and retrying while Etherscan reports the rate limit
+// as exceeded. No call is made once the context is done.
+func (t *Tracker) call(fn func() error) error {
+	delay := RateLimitBackoff
+	for attempt := 1; ; attempt++ {
+		if err := t.ctx.Err(); err != nil {
+			return err
+		}
+		err := fn()
+		if !errors.Is(err, etherscan.ErrRateLimited) || attempt == RateLimitAttempts {
+			return err
+		}
+		slog.Warn("Rate limited by Etherscan, backing off", "retry_in", delay, "attempt", attempt)
+		t.sleep(delay)
+		delay *= 2
+	}
+}
+
+// fatal reports whether a fetch error must stop the whole run instead of only the source
+// it occurred in, because every further request would fail the same way or the run was cancelled
+func fatal(err error) bool {
+	return errors.Is(err, etherscan.ErrInvalidAPIKey) || errors.Is(err, etherscan.ErrRateLimited) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
 
+// progressStage names the progress stage fetching one source of an address
+func progressStage(address, source string) string {
+	return fmt.Sprintf("%s %s transactions", address, source)
+}
+
+// LogSummary logs a summary of the export operation. It goes to the log on stderr like
+// all other messages, so it keeps standard output clean, follows the log format and is
+// left out of quiet runs.
+func LogSummary(summary map[string]interface{}) {
+	var attrs []any
+	if name, ok := summary["portfolio"].(string); ok {
+		attrs = append(attrs, "portfolio", name, "wallets", summary["wallets"])
+	}
+	attrs = append(attrs,
+		"transactions", summary["total_transactions"],
+		"assets", summary["unique_assets"],
+		"file", summary["filename"],
+	)
+	if partitions, ok := summary["partitions"].(int); ok {
+		attrs = append(attrs, "partitions", partitions)
+	}
 	if typeCounts, ok := summary["transaction_types"].(map[models.TransactionType]int); ok {
-		fmt.Printf("\n📊 Transaction Types:\n")
-		for txType, count := range typeCounts {
-			fmt.Printf("   %s: %d\n", txType, count)
+		attrs = append(attrs, slog.Group("types", countAttrs(typeCounts, "")...))
+	}
+	if directionCounts, ok := summary["directions"].(map[models.Direction]int); ok && len(directionCounts) > 0 {
+		attrs = append(attrs, slog.Group("directions", countAttrs(directionCounts, "Other")...))
+	}
+	slog.Info("Export summary", attrs...)
+}
+
+// countAttrs turns counts into log attributes sorted by name, so summaries read the same
+// on every run; an empty name is logged as unnamed
+func countAttrs[K ~string](counts map[K]int, unnamed string) []any {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		label := name
+		if label == "" {
+			label = unnamed
 		}
+		attrs = append(attrs, slog.Int(label, counts[K(name)]))
 	}
+	return attrs
 }
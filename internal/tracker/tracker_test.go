@@ -0,0 +1,195 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/processor"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogSummaryIsSorted(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	LogSummary(map[string]interface{}{
+		"total_transactions": 3,
+		"unique_assets":      2,
+		"filename":           "out.csv",
+		"transaction_types": map[models.TransactionType]int{
+			models.InternalTx:    1,
+			models.ERC20Transfer: 1,
+			models.ETHTransfer:   1,
+		},
+		"directions": map[models.Direction]int{
+			models.DirectionOut: 1,
+			models.DirectionIn:  1,
+			"":                  1,
+		},
+	})
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("summary is not a single record: %q", line)
+	}
+	order := []string{`"types.ERC-20 Transfer"=1`, `"types.ETH Transfer"=1`, `"types.Internal Transfer"=1`, "directions.Other=1", "directions.In=1", "directions.Out=1"}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(line, want)
+		if i < 0 {
+			t.Fatalf("summary lacks %s: %s", want, line)
+		}
+		if i < last {
+			t.Errorf("%s is out of order: %s", want, line)
+		}
+		last = i
+	}
+}
+
+func TestLogSummaryQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	LogSummary(map[string]interface{}{"total_transactions": 1, "unique_assets": 1, "filename": "out.csv"})
+	if buf.Len() > 0 {
+		t.Errorf("quiet run logged the summary: %s", buf.String())
+	}
+}
+
+func TestERC1155TransfersAreFetched(t *testing.T) {
+	f := newFakeEtherscan(t, func(params url.Values) string {
+		if params.Get("action") == "token1155tx" {
+			return `{"status":"1","message":"OK","result":[{"blockNumber":"150","timeStamp":"1700000000","hash":"0xbbb","from":"0x2222222222222222222222222222222222222222","to":"` + testAddress + `","contractAddress":"0x3333333333333333333333333333333333333333","tokenID":"7","tokenValue":"12","tokenName":"Items","tokenSymbol":"ITM","gasPrice":"1","gasUsed":"1"}]}`
+		}
+		return noTransactions
+	})
+
+	types, err := processor.ParseTypes("erc1155")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := newTestTracker(f)
+	tr.SetFilter(processor.Filter{Types: types})
+	txs := streamAll(t, tr)
+
+	if len(txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(txs))
+	}
+	tx := txs[0]
+	if tx.TransactionType != models.ERC1155Transfer || tx.TokenID != "7" || tx.ValueFormatted != "12" || tx.AssetSymbol != "ITM" {
+		t.Errorf("unexpected transfer %+v", tx)
+	}
+	for _, params := range f.requests {
+		if action := params.Get("action"); action != "token1155tx" {
+			t.Errorf("fetched %s for ERC-1155 transfers only", action)
+		}
+	}
+}
+
+func TestResultWindowTooLargeSplitsRange(t *testing.T) {
+	blocks := []int{10, 400, 800, 999}
+	f := newFakeEtherscan(t, func(params url.Values) string {
+		if params.Get("action") != "txlist" {
+			return noTransactions
+		}
+		start, _ := strconv.Atoi(params.Get("startblock"))
+		end, _ := strconv.Atoi(params.Get("endblock"))
+		if end-start > 300 {
+			return `{"status":"0","message":"NOTOK","result":"Result window is too large, PageNo x Offset size must be less than or equal to 10000"}`
+		}
+		var results []string
+		for _, block := range blocks {
+			if block >= start && block <= end {
+				results = append(results, normalTx(fmt.Sprintf("0x%d", block), block))
+			}
+		}
+		if len(results) == 0 {
+			return noTransactions
+		}
+		return `{"status":"1","message":"OK","result":[` + strings.Join(results, ",") + `]}`
+	})
+
+	tr := newTestTracker(f)
+	tr.SetFilter(processor.Filter{EndBlock: 1000})
+	txs := streamAll(t, tr)
+
+	var got []string
+	for _, tx := range txs {
+		got = append(got, tx.BlockNumber)
+	}
+	sort.Strings(got)
+	if fmt.Sprint(got) != "[10 400 800 999]" {
+		t.Errorf("got blocks %v, want each transaction once", got)
+	}
+
+	// Every range that was too large was split in two halves covering it
+	split := 0
+	for _, params := range f.requests {
+		if params.Get("action") != "txlist" {
+			continue
+		}
+		if params.Get("page") != "1" {
+			t.Errorf("paged on after a window error: %v", params)
+		}
+		start, _ := strconv.Atoi(params.Get("startblock"))
+		end, _ := strconv.Atoi(params.Get("endblock"))
+		if end-start > 300 {
+			split++
+		}
+	}
+	if split == 0 {
+		t.Error("the range was never split")
+	}
+}
+
+func TestCancelStopsBetweenRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string // Request during which the run is cancelled
+		body   string
+	}{
+		{"normal transactions", "txlist", `{"status":"1","message":"OK","result":[` + normalTx("0xaaa", 150) + `]}`},
+		{"token transfers", "tokentx", noTransactions},
+		{"rate limit back-off", "txlist", `{"status":"0","message":"NOTOK","result":"Max calls per sec rate limit reached (5/sec)"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			f := newFakeEtherscan(t, func(params url.Values) string {
+				if params.Get("action") == tt.action {
+					cancel()
+					return tt.body
+				}
+				return noTransactions
+			})
+
+			tr := newTestTracker(f)
+			tr.SetContext(ctx)
+			started := time.Now()
+			err := tr.StreamTransactions(testAddress, func(*models.Transaction) error { return nil })
+
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want the cancellation", err)
+			}
+			if elapsed := time.Since(started); elapsed > RateLimitBackoff/2 {
+				t.Errorf("stopped after %s", elapsed)
+			}
+			last := f.requests[len(f.requests)-1]
+			if action := last.Get("action"); action != tt.action {
+				t.Errorf("requested %s after the cancellation", action)
+			}
+		})
+	}
+}
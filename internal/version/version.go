@@ -0,0 +1,5 @@
+package version
+
+// Version of the tool, set at build time with
+// -ldflags "-X crypto-acc-tracking/internal/version.Version=v1.2.3"
+var Version = "dev"
@@ -0,0 +1,294 @@
+package watch
+
+import (
+	"context"
+	"crypto-acc-tracking/internal/models"
+	"crypto-acc-tracking/internal/output"
+	"crypto-acc-tracking/internal/processor"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReorgDepth is the number of recent blocks re-checked on every poll unless set
+const DefaultReorgDepth = 12
+
+// Source provides the chain data a Watcher polls. The tracker fetching from Etherscan is
+// the usual source; any other implementation, such as a fixed set of blocks, can stand in.
+type Source interface {
+	// CurrentBlock returns the number of the latest block
+	CurrentBlock() (int, error)
+	// StreamBlocks emits the processed transactions of an address in a block range
+	StreamBlocks(address string, startBlock, endBlock int, emit func(*models.Transaction) error) error
+}
+
+// Kind identifies what happened to a watched transaction
+type Kind string
+
+const (
+	Added     Kind = "added"     // Newly seen, still within the reorg depth
+	Removed   Kind = "removed"   // Reported as added before, but no longer on the chain
+	Confirmed Kind = "confirmed" // Deeper than the reorg depth, so no longer re-checked
+)
+
+// Event reports a change to one transaction of the watched address
+type Event struct {
+	Kind        Kind
+	Transaction *models.Transaction
+}
+
+// Options configures a Watcher
+type Options struct {
+	Interval   time.Duration // Time between polls
+	ReorgDepth int           // Number of recent blocks re-checked on every poll
+	StartBlock int           // First block to watch, 0 for the block after the current one
+	CursorFile string        // File keeping the first unconfirmed block, to resume from after a restart
+}
+
+// Watcher follows the chain for new transactions of one address. Every poll fetches the
+// blocks since the previous poll plus the last ReorgDepth blocks again, so transactions
+// dropped by a reorg are reported as removed and those moved to another block are
+// reported as removed and added again.
+type Watcher struct {
+	source    Source
+	address   string
+	owned     map[string]bool
+	options   Options
+	processor *processor.Processor
+
+	start   int                            // First block watched, 0 until resolved by Start or the first poll
+	next    int                            // First block not polled yet
+	pending map[string]*models.Transaction // Unconfirmed transactions by key
+}
+
+// New creates a watcher for an address
+func New(source Source, address string, options Options) *Watcher {
+	address = strings.ToLower(address)
+	if options.ReorgDepth < 0 {
+		options.ReorgDepth = 0
+	}
+	return &Watcher{
+		source:    source,
+		address:   address,
+		owned:     map[string]bool{address: true},
+		options:   options,
+		processor: processor.New(),
+		pending:   make(map[string]*models.Transaction),
+	}
+}
+
+// Start returns the first block watched: the configured start block, the block saved in
+// the cursor file, or the block after the current one. Poll resolves it on its first call;
+// calling Start beforehand lets callers prepare for it, such as loading earlier history.
+func (w *Watcher) Start() (int, error) {
+	if w.start == 0 {
+		head, err := w.source.CurrentBlock()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get current block: %w", err)
+		}
+		if err := w.begin(head); err != nil {
+			return 0, err
+		}
+	}
+	return w.start, nil
+}
+
+// begin resolves the first block watched once, given the current head
+func (w *Watcher) begin(head int) error {
+	if w.start != 0 {
+		return nil
+	}
+	w.start = w.options.StartBlock
+	if w.options.CursorFile != "" {
+		block, err := loadCursor(w.options.CursorFile, w.address)
+		if err != nil {
+			return err
+		}
+		if block > 0 {
+			w.start = block
+			slog.Info("Resuming from cursor", "file", w.options.CursorFile, "block", block)
+		}
+	}
+	if w.start <= 0 {
+		w.start = head + 1
+	}
+	w.next = w.start
+	slog.Info("Watching", "address", w.address, "start_block", w.start, "reorg_depth", w.options.ReorgDepth)
+	return nil
+}
+
+// Poll checks the source once and returns what changed since the previous poll: removed
+// transactions first, then added and confirmed ones in chain order
+func (w *Watcher) Poll() ([]Event, error) {
+	head, err := w.source.CurrentBlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block: %w", err)
+	}
+
+	if err := w.begin(head); err != nil {
+		return nil, err
+	}
+
+	from := max(w.next-w.options.ReorgDepth, w.start)
+	if head < from {
+		return nil, nil
+	}
+
+	current := make(map[string]*models.Transaction)
+	err = w.source.StreamBlocks(w.address, from, head, func(tx *models.Transaction) error {
+		w.processor.AssignDirection(tx, w.owned)
+		current[key(tx)] = tx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var removed, added, confirmed []*models.Transaction
+	for k, tx := range w.pending {
+		if current[k] == nil && blockNumber(tx) >= from {
+			removed = append(removed, tx)
+			delete(w.pending, k)
+		}
+	}
+	for k, tx := range current {
+		if w.pending[k] == nil {
+			added = append(added, tx)
+			w.pending[k] = tx
+		}
+	}
+	for k, tx := range w.pending {
+		if blockNumber(tx) <= head-w.options.ReorgDepth {
+			confirmed = append(confirmed, tx)
+			delete(w.pending, k)
+		}
+	}
+	w.next = head + 1
+
+	var events []Event
+	for _, group := range []struct {
+		kind         Kind
+		transactions []*models.Transaction
+	}{{Removed, removed}, {Added, added}, {Confirmed, confirmed}} {
+		sort.SliceStable(group.transactions, func(i, j int) bool {
+			return processor.Ascending.Less(group.transactions[i], group.transactions[j])
+		})
+		for _, tx := range group.transactions {
+			events = append(events, Event{Kind: group.kind, Transaction: tx})
+		}
+	}
+	return events, nil
+}
+
+// Run polls every interval and hands the events of each poll to handle until the context
+// is cancelled. Failed polls are reported and retried; an error from handle stops the watcher.
+// The cursor is saved once handle has processed a poll, so a restart never skips blocks.
+func (w *Watcher) Run(ctx context.Context, handle func([]Event) error) error {
+	for {
+		events, err := w.Poll()
+		if err != nil {
+			slog.Warn("Poll failed", "retry_in", w.options.Interval, "error", err)
+		}
+		if len(events) > 0 {
+			if err := handle(events); err != nil {
+				return err
+			}
+		}
+		if err == nil {
+			if err := w.saveCursor(); err != nil {
+				slog.Warn("Failed to save cursor", "file", w.options.CursorFile, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.options.Interval):
+		}
+	}
+}
+
+// Cursor returns the first block whose transactions are not all confirmed yet, where a
+// restarted watcher resumes: unconfirmed transactions are reported as added again, while
+// confirmed ones are not repeated
+func (w *Watcher) Cursor() int {
+	if w.start == 0 {
+		return 0
+	}
+	return max(w.next-w.options.ReorgDepth, w.start)
+}
+
+// saveCursor writes the cursor to the cursor file, if one is set
+func (w *Watcher) saveCursor() error {
+	if w.options.CursorFile == "" || w.start == 0 {
+		return nil
+	}
+	return writeCursor(w.options.CursorFile, w.address, w.Cursor())
+}
+
+// Pending returns the number of transactions not confirmed yet
+func (w *Watcher) Pending() int {
+	return len(w.pending)
+}
+
+// key identifies a transfer in a block, so a transfer moved to another block by a reorg
+// counts as a different one
+func key(tx *models.Transaction) string {
+	return processor.TransferKey(tx) + "@" + tx.BlockNumber
+}
+
+// blockNumber returns the block of a transaction, or 0 if it cannot be parsed
+func blockNumber(tx *models.Transaction) int {
+	block, _ := strconv.Atoi(tx.BlockNumber)
+	return block
+}
+
+// cursor is the content of a cursor file
+type cursor struct {
+	Address string `json:"address"`
+	Block   int    `json:"block"` // First block not confirmed yet
+}
+
+// loadCursor returns the block saved in a cursor file, or 0 when the file does not exist
+func loadCursor(filename, address string) (int, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("failed to parse cursor %s: %w", filename, err)
+	}
+	if !strings.EqualFold(c.Address, address) {
+		return 0, fmt.Errorf("cursor %s belongs to %s, not %s", filename, c.Address, address)
+	}
+	return c.Block, nil
+}
+
+// writeCursor saves the block to resume from atomically
+func writeCursor(filename, address string, block int) error {
+	data, err := json.Marshal(cursor{Address: address, Block: block})
+	if err != nil {
+		return fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	file, err := output.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Abort()
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	return file.Commit()
+}
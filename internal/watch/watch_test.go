@@ -0,0 +1,241 @@
+package watch
+
+import (
+	"context"
+	"crypto-acc-tracking/internal/models"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const wallet = "0xa39b189482f984388a34460636fea9eb181ad1a6"
+
+// fakeSource is a chain whose head and transactions the test sets between polls
+type fakeSource struct {
+	head     int
+	blocks   map[int][]string // Transaction hashes by block
+	requests [][2]int         // Block ranges asked for
+}
+
+func newFakeSource(head int) *fakeSource {
+	return &fakeSource{head: head, blocks: make(map[int][]string)}
+}
+
+func (s *fakeSource) CurrentBlock() (int, error) {
+	return s.head, nil
+}
+
+func (s *fakeSource) StreamBlocks(address string, startBlock, endBlock int, emit func(*models.Transaction) error) error {
+	s.requests = append(s.requests, [2]int{startBlock, endBlock})
+	for block := startBlock; block <= endBlock; block++ {
+		for index, hash := range s.blocks[block] {
+			err := emit(&models.Transaction{
+				Hash:             hash,
+				DateTime:         time.Unix(int64(block)*12, 0),
+				FromAddress:      "0xsender",
+				ToAddress:        address,
+				TransactionType:  models.ETHTransfer,
+				Value:            big.NewInt(1),
+				BlockNumber:      strconv.Itoa(block),
+				TransactionIndex: strconv.Itoa(index),
+				Chain:            "ethereum",
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// summary describes events as "kind hash" strings
+func summary(events []Event) []string {
+	var lines []string
+	for _, event := range events {
+		lines = append(lines, string(event.Kind)+" "+event.Transaction.Hash)
+	}
+	return lines
+}
+
+// poll polls once and compares the events with want
+func poll(t *testing.T, w *Watcher, want ...string) {
+	t.Helper()
+	events, err := w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := summary(events)
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got events %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPollAddsNewTransactions(t *testing.T) {
+	source := newFakeSource(100)
+	w := New(source, wallet, Options{ReorgDepth: 3})
+
+	// The first poll only sets the start after the current block
+	poll(t, w)
+	source.blocks[101] = []string{"0xa"}
+	source.blocks[102] = []string{"0xb", "0xc"}
+	source.head = 102
+	poll(t, w, "added 0xa", "added 0xb", "added 0xc")
+
+	// Already reported transactions in the re-checked blocks are not repeated
+	source.head = 103
+	poll(t, w)
+	if w.Pending() != 3 {
+		t.Errorf("Pending() = %d, want 3", w.Pending())
+	}
+
+	if events, _ := w.Poll(); len(events) != 0 {
+		t.Errorf("a poll without a new block reported %v", summary(events))
+	}
+}
+
+func TestPollRemovesReorgedTransactions(t *testing.T) {
+	source := newFakeSource(100)
+	w := New(source, wallet, Options{ReorgDepth: 5})
+	poll(t, w)
+
+	source.blocks[101] = []string{"0xa"}
+	source.blocks[102] = []string{"0xb"}
+	source.head = 102
+	poll(t, w, "added 0xa", "added 0xb")
+
+	// A reorg drops 0xa and moves 0xb to the next block
+	delete(source.blocks, 101)
+	source.blocks[102] = nil
+	source.blocks[103] = []string{"0xb"}
+	source.head = 103
+	poll(t, w, "removed 0xa", "removed 0xb", "added 0xb")
+
+	if got := source.requests[len(source.requests)-1]; got != [2]int{101, 103} {
+		t.Errorf("re-checked blocks %v, want the reorg depth back to the start", got)
+	}
+}
+
+func TestPollConfirmsAtDepth(t *testing.T) {
+	source := newFakeSource(100)
+	w := New(source, wallet, Options{ReorgDepth: 2})
+	poll(t, w)
+
+	source.blocks[101] = []string{"0xa"}
+	source.head = 101
+	poll(t, w, "added 0xa")
+
+	// Block 101 is at depth 1 below head 102, still within the reorg depth
+	source.head = 102
+	poll(t, w)
+
+	// At head 103 it is two blocks deep and confirmed; new ones are added in the same poll
+	source.blocks[103] = []string{"0xb"}
+	source.head = 103
+	poll(t, w, "added 0xb", "confirmed 0xa")
+
+	// Confirmed transactions are no longer re-checked, so a late reorg does not remove them
+	delete(source.blocks, 101)
+	source.head = 104
+	poll(t, w)
+	if got := source.requests[len(source.requests)-1]; got != [2]int{102, 104} {
+		t.Errorf("re-checked blocks %v, want [102 104]", got)
+	}
+}
+
+func TestCursorPersistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cursor.json")
+	source := newFakeSource(100)
+	source.blocks[101] = []string{"0xa"}
+	source.blocks[104] = []string{"0xb"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := New(source, wallet, Options{ReorgDepth: 2, StartBlock: 101, CursorFile: file})
+	source.head = 104
+	var events []Event
+	err := w.Run(ctx, func(polled []Event) error {
+		events = append(events, polled...)
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summary(events); len(got) != 3 || got[2] != "confirmed 0xa" {
+		t.Fatalf("got events %v, want 0xa confirmed at head 104", got)
+	}
+
+	block, err := loadCursor(file, wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block != 103 {
+		t.Fatalf("saved cursor %d, want 103, the first unconfirmed block", block)
+	}
+
+	// A restarted watcher resumes at the cursor, ahead of its start block, while blocks
+	// were mined; the unconfirmed 0xb is reported again, the confirmed 0xa is not
+	source.blocks[106] = []string{"0xc"}
+	source.head = 106
+	restarted := New(source, wallet, Options{ReorgDepth: 2, StartBlock: 101, CursorFile: file})
+	poll(t, restarted, "added 0xb", "added 0xc", "confirmed 0xb")
+	if got := source.requests[len(source.requests)-1]; got != [2]int{103, 106} {
+		t.Errorf("resumed with blocks %v, want [103 106]", got)
+	}
+
+	if _, err := loadCursor(file, "0x0000000000000000000000000000000000000001"); err == nil {
+		t.Error("loadCursor() accepted the cursor of another address")
+	}
+}
+
+func TestStartResolvesFirstBlock(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cursor.json")
+	source := newFakeSource(100)
+	source.blocks[101] = []string{"0xa"}
+
+	for _, tc := range []struct {
+		name    string
+		options Options
+		want    int
+	}{
+		{"after head", Options{}, 101},
+		{"start block", Options{StartBlock: 50}, 50},
+		{"missing cursor", Options{StartBlock: 50, CursorFile: file}, 50},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := New(source, wallet, tc.options)
+			start, err := w.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tc.want {
+				t.Errorf("Start() = %d, want %d", start, tc.want)
+			}
+		})
+	}
+
+	// Start before the first poll keeps the start block once the chain moves on
+	w := New(source, wallet, Options{ReorgDepth: 2, CursorFile: file})
+	if start, err := w.Start(); err != nil || start != 101 {
+		t.Fatalf("Start() = %d, %v, want 101", start, err)
+	}
+	source.head = 110
+	poll(t, w, "added 0xa", "confirmed 0xa")
+	if err := w.saveCursor(); err != nil {
+		t.Fatal(err)
+	}
+	if start, _ := w.Start(); start != 101 {
+		t.Errorf("Start() after a poll = %d, want 101", start)
+	}
+
+	restarted := New(source, wallet, Options{StartBlock: 50, CursorFile: file})
+	if start, err := restarted.Start(); err != nil || start != 109 {
+		t.Errorf("Start() with a cursor = %d, %v, want 109, the saved cursor", start, err)
+	}
+}
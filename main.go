@@ -2,11 +2,13 @@ package main
 
 import (
 	"crypto-acc-tracking/cmd"
-	"log"
+	"log/slog"
+	"os"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		log.Fatal(err)
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 }